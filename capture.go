@@ -0,0 +1,114 @@
+package sqlmock
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Call records an invocation which matched a sql expectation,
+// so that its payload can be asserted after the code under test
+// returns. Returned by ExpectedSql.Calls.
+type Call struct {
+	// Query is the sql text the call was made with.
+	Query string
+
+	// Args are the arguments of the call, named or positional.
+	Args []driver.NamedValue
+
+	// Deadline is the deadline of the call context, if it had one.
+	Deadline    time.Time
+	HasDeadline bool
+
+	// Time is when the call was matched.
+	Time time.Time
+
+	// TxID identifies the transaction the call ran in, starting
+	// from 1 in the order transactions begin. It is 0 when the
+	// call ran outside of any transaction.
+	TxID int
+}
+
+// newCall records a matched call, the caller holds the expectation lock
+func newCall(ctx context.Context, query string, args []driver.NamedValue, tx *mockTx) Call {
+	call := Call{
+		Query: query,
+		Args:  append([]driver.NamedValue(nil), args...),
+		Time:  time.Now(),
+	}
+	call.Deadline, call.HasDeadline = ctx.Deadline()
+	if tx != nil {
+		call.TxID = tx.id
+	}
+	return call
+}
+
+// Calls returns every call which matched the expectation so far,
+// in the order they were made.
+func (e *ExpectedSql) Calls() []Call {
+	e.Lock()
+	defer e.Unlock()
+	return append([]Call(nil), e.calls...)
+}
+
+// Calls returns every call which matched the query so far.
+func (e *ExpectedQuery) Calls() []Call {
+	return e.ex.Calls()
+}
+
+// Calls returns every call which matched the exec so far.
+func (e *ExpectedExec) Calls() []Call {
+	return e.ex.Calls()
+}
+
+// ArgCaptor is a Matcher which matches any argument and records
+// it every time the call matches the expectation, unlike a check
+// made by WithArgsCheck, arguments of candidates which eventually
+// do not match are not recorded.
+type ArgCaptor struct {
+	mu     sync.Mutex
+	values []driver.Value
+}
+
+// NewArgCaptor creates an ArgCaptor to be passed
+// as an expected argument to WithArgs or WithNamedArgs.
+func NewArgCaptor() *ArgCaptor {
+	return &ArgCaptor{}
+}
+
+// Match satisfies sqlmock.Matcher interface
+func (a *ArgCaptor) Match(v driver.Value) bool {
+	return true
+}
+
+func (a *ArgCaptor) capture(v driver.Value) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.values = append(a.values, v)
+}
+
+// Values returns the captured arguments, one for every matched call.
+func (a *ArgCaptor) Values() []driver.Value {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]driver.Value(nil), a.values...)
+}
+
+// Last returns the argument captured by the last matched call,
+// or nil if no call matched yet.
+func (a *ArgCaptor) Last() driver.Value {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.values) == 0 {
+		return nil
+	}
+	return a.values[len(a.values)-1]
+}
+
+func (a *ArgCaptor) String() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return fmt.Sprintf("ArgCaptor(%d captured)", len(a.values))
+}
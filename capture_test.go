@@ -0,0 +1,105 @@
+package sqlmock
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExpectedSqlCalls(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.MatchExpectationsInOrder(false)
+
+	mock.ExpectBegin()
+	insert := mock.ExpectExec("INSERT INTO users").
+		WithArgs(Any(), Any()).
+		WillReturnResult(NewResult(1, 1)).
+		Times(2)
+	mock.ExpectCommit()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+	}
+	if _, err := tx.Exec("INSERT INTO users(name, email) VALUES (?, ?)", "john", "john@example.com"); err != nil {
+		t.Fatalf("an error '%s' was not expected while inserting a row", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("an error '%s' was not expected when committing", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if _, err := db.ExecContext(ctx, "INSERT INTO users(name, email) VALUES (?, ?)", "jane", sql.Named("email", "jane@example.com")); err != nil {
+		t.Fatalf("an error '%s' was not expected while inserting a row", err)
+	}
+
+	calls := insert.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 recorded calls, but got %d", len(calls))
+	}
+
+	if calls[0].TxID != 1 || calls[0].HasDeadline {
+		t.Errorf("expected the first call in transaction 1 without deadline, but got %+v", calls[0])
+	}
+	want := []driver.NamedValue{{Ordinal: 1, Value: "john"}, {Ordinal: 2, Value: "john@example.com"}}
+	if !reflect.DeepEqual(calls[0].Args, want) {
+		t.Errorf("expected args %+v, but got %+v", want, calls[0].Args)
+	}
+
+	if calls[1].TxID != 0 || !calls[1].HasDeadline {
+		t.Errorf("expected the second call outside of a transaction with a deadline, but got %+v", calls[1])
+	}
+	if calls[1].Args[1].Name != "email" || calls[1].Query != "INSERT INTO users(name, email) VALUES (?, ?)" {
+		t.Errorf("unexpected second call: %+v", calls[1])
+	}
+	if calls[1].Time.Before(calls[0].Time) {
+		t.Errorf("expected calls in order, but got %s before %s", calls[1].Time, calls[0].Time)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestArgCaptor(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.MatchExpectationsInOrder(false)
+
+	rejected := NewArgCaptor()
+	names := NewArgCaptor()
+	mock.ExpectExec("UPDATE users").
+		WithArgs(rejected, 0).
+		WillReturnResult(NewResult(0, 1))
+	mock.ExpectExec("UPDATE users").
+		WithArgs(names, Any()).
+		WillReturnResult(NewResult(0, 1)).
+		Times(2)
+
+	for i, name := range []string{"john", "jane"} {
+		if _, err := db.Exec("UPDATE users SET name = ? WHERE id = ?", name, i+1); err != nil {
+			t.Fatalf("an error '%s' was not expected while updating", err)
+		}
+	}
+
+	if want := []driver.Value{"john", "jane"}; !reflect.DeepEqual(names.Values(), want) {
+		t.Errorf("expected captured values %v, but got %v", want, names.Values())
+	}
+	if names.Last() != "jane" {
+		t.Errorf("expected last captured value jane, but got %v", names.Last())
+	}
+	if len(rejected.Values()) != 0 {
+		t.Errorf("expected no values captured by a candidate which did not match, but got %v", rejected.Values())
+	}
+}
@@ -0,0 +1,90 @@
+package sqlmock
+
+import "reflect"
+
+// Column is a mocked column definition, describing the
+// metadata returned by *sql.Rows.ColumnTypes.
+type Column struct {
+	name         string
+	dbType       string
+	scanType     reflect.Type
+	nullable     bool
+	nullableSet  bool
+	length       int64
+	lengthSet    bool
+	precision    int64
+	scale        int64
+	precisionSet bool
+}
+
+// NewColumn creates a Column definition with the given name,
+// to be used with NewRowsWithColumnDefinition.
+func NewColumn(name string) *Column {
+	return &Column{name: name}
+}
+
+// OfType sets the database type name of the column, and the
+// scan type derived from the sample value, for example
+// OfType("VARCHAR", "") or OfType("BIGINT", int64(0)).
+func (c *Column) OfType(dbType string, sampleValue interface{}) *Column {
+	c.dbType = dbType
+	if sampleValue != nil {
+		c.scanType = reflect.TypeOf(sampleValue)
+	}
+	return c
+}
+
+// Nullable sets whether the column may hold NULL values.
+func (c *Column) Nullable(nullable bool) *Column {
+	c.nullable, c.nullableSet = nullable, true
+	return c
+}
+
+// WithLength sets the length of a variable length column type.
+func (c *Column) WithLength(length int64) *Column {
+	c.length, c.lengthSet = length, true
+	return c
+}
+
+// WithPrecisionAndScale sets the precision and scale of a decimal column type.
+func (c *Column) WithPrecisionAndScale(precision, scale int64) *Column {
+	c.precision, c.scale, c.precisionSet = precision, scale, true
+	return c
+}
+
+// Name returns the column name.
+func (c *Column) Name() string {
+	return c.name
+}
+
+// DbType returns the database type name of the column.
+func (c *Column) DbType() string {
+	return c.dbType
+}
+
+// ScanType returns the type suitable for scanning the column into,
+// interface{} if the column type was not defined.
+func (c *Column) ScanType() reflect.Type {
+	if c.scanType == nil {
+		return reflect.TypeOf(new(interface{})).Elem()
+	}
+	return c.scanType
+}
+
+// IsNullable returns whether the column may hold NULL values,
+// ok is false if it was not defined.
+func (c *Column) IsNullable() (nullable, ok bool) {
+	return c.nullable, c.nullableSet
+}
+
+// Length returns the length of a variable length column type,
+// ok is false if it was not defined.
+func (c *Column) Length() (length int64, ok bool) {
+	return c.length, c.lengthSet
+}
+
+// PrecisionScale returns the precision and scale of a decimal
+// column type, ok is false if they were not defined.
+func (c *Column) PrecisionScale() (precision, scale int64, ok bool) {
+	return c.precision, c.scale, c.precisionSet
+}
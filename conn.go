@@ -0,0 +1,175 @@
+package sqlmock
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"time"
+)
+
+var _ driver.Validator = (*conn)(nil)
+var _ driver.SessionResetter = (*conn)(nil)
+
+// conn is a single database connection opened by the driver.
+// All connections of a mock share its expectations, but each
+// one has its own transaction state, like a real connection.
+type conn struct {
+	*sqlmock
+	index int
+	tx    *mockTx
+	bad   bool
+}
+
+// ConnStats describes the connections opened
+// by database/sql for a mock database.
+type ConnStats struct {
+	// Opened is the number of connections opened so far.
+	Opened int
+	// Closed is the number of connections closed so far.
+	Closed int
+	// Open is the number of connections currently open.
+	Open int
+}
+
+// dial opens a new connection, unless a pending
+// connect expectation tells it to fail
+func (c *sqlmock) dial(ctx context.Context) (*conn, error) {
+	ex, err := c.connectExpectation()
+	if ex != nil {
+		select {
+		case <-time.After(ex.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.drv.Lock()
+	defer c.drv.Unlock()
+	return c.connect(), nil
+}
+
+// connectExpectation triggers the first connect expectation
+// which may still be matched, connections opened while there
+// is none are allowed, since the pool opens them at will
+func (c *sqlmock) connectExpectation() (*ExpectedConnect, error) {
+	for _, e := range c.connectExpected {
+		e.Lock()
+		if e.exhausted() {
+			e.Unlock()
+			continue
+		}
+
+		e.triggered++
+		e.Unlock()
+		return e, e.err
+	}
+	return nil, nil
+}
+
+// connect opens a new connection, the driver lock must be held
+func (c *sqlmock) connect() *conn {
+	cn := &conn{sqlmock: c, index: c.connects}
+	c.connects++
+	c.opened++
+	return cn
+}
+
+func (c *sqlmock) ConnStats() ConnStats {
+	c.drv.Lock()
+	defer c.drv.Unlock()
+
+	return ConnStats{Opened: c.connects, Closed: c.closes, Open: c.opened}
+}
+
+// verify checks that a matched expectation is triggered on the
+// connection and inside of the transaction it was declared for
+func (c *conn) verify(e *commonExpectation, call string) error {
+	if err := c.verifyConn(e, call); err != nil {
+		return err
+	}
+	return c.verifyTx(e.group, call)
+}
+
+// verifyConn checks that an expectation pinned with
+// OnConn is triggered on the connection it is pinned to
+func (c *conn) verifyConn(e *commonExpectation, call string) error {
+	if e.pinned && e.connIndex != c.index {
+		return newError(ErrWrongConnection, "%s was expected on connection %d, but it was called on connection %d", call, e.connIndex, c.index)
+	}
+	return nil
+}
+
+// nextExpectation finds the pending expectation triggered by a call
+// and returns it locked. match is called with every pending expectation
+// locked, it tells whether the expectation is of the called kind and,
+// if so, why it does not accept the call. In order, the expectation
+// which is not fulfilled yet must accept the call, otherwise the ones
+// which do not accept it are skipped, for example when they are pinned
+// to another connection, and the first reason is returned if none does.
+func (c *conn) nextExpectation(call string, match func(expectation) (bool, error)) (expectation, error) {
+	var rejected error
+	var fulfilled int
+	for _, next := range c.expected {
+		next.Lock()
+		if next.exhausted() {
+			next.Unlock()
+			fulfilled++
+			continue
+		}
+
+		required := c.ordered && !next.fulfilled()
+		ok, err := match(next)
+		if ok && err == nil {
+			return next, nil
+		}
+
+		next.Unlock()
+		if required && ok {
+			return nil, err
+		}
+		if required {
+			return nil, newError(ErrUnexpectedCall, "%s, was not expected, next expectation is: %s", call, next)
+		}
+		if ok && rejected == nil {
+			rejected = err
+		}
+	}
+
+	if rejected != nil {
+		return nil, rejected
+	}
+	msg := call + " was not expected"
+	if fulfilled == len(c.expected) {
+		msg = "all expectations were already fulfilled, " + msg
+	}
+	return nil, newError(ErrUnexpectedCall, "%s", msg)
+}
+
+// markBad flags the connection as broken when err is driver.ErrBadConn,
+// so that database/sql discards it instead of putting it back to the pool
+func (c *conn) markBad(err error) error {
+	if errors.Is(err, driver.ErrBadConn) {
+		c.connLock.Lock()
+		c.bad = true
+		c.connLock.Unlock()
+	}
+	return err
+}
+
+// IsValid meets https://golang.org/pkg/database/sql/driver/#Validator
+func (c *conn) IsValid() bool {
+	c.connLock.Lock()
+	defer c.connLock.Unlock()
+	return !c.bad
+}
+
+// ResetSession meets https://golang.org/pkg/database/sql/driver/#SessionResetter
+func (c *conn) ResetSession(ctx context.Context) error {
+	if !c.IsValid() {
+		return driver.ErrBadConn
+	}
+	return nil
+}
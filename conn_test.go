@@ -0,0 +1,242 @@
+package sqlmock
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDistinctConnections(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	conn1, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	conn2, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// the first connection is the one opened by New to ping the database
+	mock.ExpectSql(Exec(), "SET search_path").WillReturnResult(NewResult(0, 0)).OnConn(0)
+	mock.ExpectSql(Exec(), "SET search_path").WillReturnResult(NewResult(0, 0)).OnConn(1)
+
+	if _, err := conn2.ExecContext(ctx, "SET search_path"); err == nil || !strings.Contains(err.Error(), "expected on connection 0, but it was called on connection 1") {
+		t.Errorf("expected a connection mismatch error, but got: %v", err)
+	}
+	if _, err := conn1.ExecContext(ctx, "SET search_path"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if _, err := conn2.ExecContext(ctx, "SET search_path"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	stats := mock.ConnStats()
+	if stats.Opened != 2 || stats.Open != 2 || stats.Closed != 0 {
+		t.Errorf("expected 2 opened and open connections, but got: %+v", stats)
+	}
+
+	db.SetMaxIdleConns(0)
+	conn2.Close()
+	if stats := mock.ConnStats(); stats.Closed != 1 || stats.Open != 1 {
+		t.Errorf("expected 1 closed and 1 open connection, but got: %+v", stats)
+	}
+	conn1.Close()
+
+	if err := mock.ExpectationsWereMet(); !errors.Is(err, ErrWrongConnection) || errors.Is(err, ErrUnmetExpectations) {
+		t.Errorf("expected only the connection mismatch to be reported, but got: %v", err)
+	}
+}
+
+func TestPinnedExpectationsUnordered(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.MatchExpectationsInOrder(false)
+
+	ctx := context.Background()
+	conn1, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer conn1.Close()
+	conn2, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer conn2.Close()
+
+	mock.ExpectExec("UPDATE x").WillReturnResult(NewResult(0, 1)).OnConn(0)
+	mock.ExpectExec("UPDATE x").WillReturnResult(NewResult(0, 2)).OnConn(1)
+	mock.ExpectBegin().OnConn(0)
+	mock.ExpectBegin().OnConn(1)
+
+	// the expectations pinned to the first connection are skipped
+	res, err := conn2.ExecContext(ctx, "UPDATE x")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if n, _ := res.RowsAffected(); n != 2 {
+		t.Errorf("expected the expectation pinned to connection 1 to match, but got %d rows affected", n)
+	}
+	tx2, err := conn2.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("unexpected error on begin: %s", err)
+	}
+
+	res, err = conn1.ExecContext(ctx, "UPDATE x")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if n, _ := res.RowsAffected(); n != 1 {
+		t.Errorf("expected the expectation pinned to connection 0 to match, but got %d rows affected", n)
+	}
+	tx1, err := conn1.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("unexpected error on begin: %s", err)
+	}
+
+	mock.ExpectRollback().OnConn(0)
+	mock.ExpectRollback().OnConn(1)
+	if err := tx2.Rollback(); err != nil {
+		t.Errorf("unexpected error on rollback: %s", err)
+	}
+	if err := tx1.Rollback(); err != nil {
+		t.Errorf("unexpected error on rollback: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestTxStateIsPerConnection(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectTx(func(tx TxExpecter) {
+		tx.ExpectSql(Exec(), "UPDATE accounts").WillReturnResult(NewResult(0, 1))
+	})
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("unexpected error on begin: %s", err)
+	}
+
+	// runs on another connection of the pool, outside of tx
+	_, err = db.Exec("UPDATE accounts")
+	if err == nil || !strings.Contains(err.Error(), "outside of any transaction") {
+		t.Errorf("expected an error about the statement running outside of the transaction, but got: %v", err)
+	}
+
+	if _, err := tx.Exec("UPDATE accounts"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Errorf("unexpected error on commit: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); !errors.Is(err, ErrWrongTx) || errors.Is(err, ErrUnmetExpectations) {
+		t.Errorf("expected only the statement outside of the transaction to be reported, but got: %v", err)
+	}
+}
+
+func TestExpectConnectFailures(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	// hold the connection opened by New, so that the pool has to dial again
+	held, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer held.Close()
+
+	refused := errors.New("connection refused")
+	mock.ExpectConnect().WillReturnError(refused).Times(2)
+	mock.ExpectConnect()
+
+	for i := 0; i < 2; i++ {
+		if err := db.Ping(); err != refused {
+			t.Errorf("attempt %d: expected connection refused error, but got: %v", i, err)
+		}
+	}
+	if err := db.Ping(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+	if stats := mock.ConnStats(); stats.Opened != 2 {
+		t.Errorf("expected 2 opened connections, but got: %+v", stats)
+	}
+}
+
+func TestBadConnRedials(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectSql(Exec(), "UPDATE users").WillReturnError(driver.ErrBadConn)
+	mock.ExpectConnect()
+	mock.ExpectSql(Exec(), "UPDATE users").WillReturnResult(NewResult(0, 1))
+
+	// database/sql discards the broken connection and retries on a new one
+	if _, err := db.Exec("UPDATE users"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+	if stats := mock.ConnStats(); stats.Opened != 2 || stats.Closed != 1 {
+		t.Errorf("expected the broken connection to be replaced, but got: %+v", stats)
+	}
+}
+
+func TestBadConnUnregistersDSN(t *testing.T) {
+	t.Parallel()
+	db, mock, err := NewWithDSN("sqlmock_bad_conn_dsn")
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	mock.ExpectSql(Exec(), "UPDATE users").WillReturnError(driver.ErrBadConn)
+	mock.ExpectConnect().WillReturnError(errors.New("connection refused"))
+
+	// the last connection to close is the broken one
+	if _, err := db.Exec("UPDATE users"); err == nil || err.Error() != "connection refused" {
+		t.Errorf("expected the redial to fail, but got: %v", err)
+	}
+	db.Close()
+
+	db, _, err = NewWithDSN("sqlmock_bad_conn_dsn")
+	if err != nil {
+		t.Fatalf("expected the dsn to be available again, but got: %s", err)
+	}
+	db.Close()
+}
@@ -0,0 +1,61 @@
+package sqlmock
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+)
+
+var _ driver.Connector = (*connector)(nil)
+var _ driver.DriverContext = (*mockDriver)(nil)
+
+// connector opens connections of a single mock database,
+// it meets http://golang.org/pkg/database/sql/driver/#Connector
+type connector struct {
+	mock *sqlmock
+}
+
+// Connect opens a new connection, it honors the context and any
+// connect expectation of the mock
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.mock.dial(ctx)
+}
+
+// Driver returns the driver of the mock database
+func (c *connector) Driver() driver.Driver {
+	return c.mock.drv
+}
+
+// OpenConnector meets http://golang.org/pkg/database/sql/driver/#DriverContext
+func (d *mockDriver) OpenConnector(dsn string) (driver.Connector, error) {
+	d.Lock()
+	defer d.Unlock()
+
+	c, ok := d.connMap[dsn]
+	if !ok {
+		return nil, fmt.Errorf("expected a connection to be available, but it is not")
+	}
+	return &connector{mock: c}, nil
+}
+
+// NewConnector creates a driver.Connector for a new mock database
+// and a mock to manage expectations. The connector is meant to be
+// used with sql.OpenDB, it neither registers a DSN nor depends on
+// the global "sqlmock" driver, so mocks created this way cannot
+// collide with each other. Accepts the same options as New.
+//
+// Unlike New, it does not ping the database, so the first connection
+// is opened by the first database/sql call.
+func NewConnector(options ...func(*sqlmock) error) (driver.Connector, Sqlmock, error) {
+	drv := &mockDriver{connMap: make(map[string]*sqlmock)}
+	smock := &sqlmock{dsn: "sqlmock_connector", drv: drv, ordered: true}
+	drv.connMap[smock.dsn] = smock
+
+	if err := smock.configure(options); err != nil {
+		return nil, nil, err
+	}
+	return &connector{mock: smock}, smock, nil
+}
@@ -0,0 +1,62 @@
+package sqlmock
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestNewConnector(t *testing.T) {
+	t.Parallel()
+	connector, mock, err := NewConnector(QueryMatcherOption(QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating a connector", err)
+	}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	mock.ExpectConnect()
+	mock.ExpectSql(Query(), "SELECT name FROM users").
+		WillReturnRows(mock.NewRows([]string{"name"}).AddRow("john"))
+
+	var name string
+	if err := db.QueryRow("SELECT name FROM users").Scan(&name); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if name != "john" {
+		t.Errorf("expected name to be john, but got %s", name)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+	if stats := mock.ConnStats(); stats.Opened != 1 {
+		t.Errorf("expected a single connection to be opened, but got: %+v", stats)
+	}
+}
+
+func TestConnectorConnectContext(t *testing.T) {
+	t.Parallel()
+	connector, mock, err := NewConnector()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating a connector", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := connector.Connect(ctx); err != context.Canceled {
+		t.Errorf("expected context canceled error, but got: %v", err)
+	}
+
+	mock.ExpectConnect().WillDelayFor(time.Second)
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := connector.Connect(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected deadline exceeded error, but got: %v", err)
+	}
+
+	if stats := mock.ConnStats(); stats.Opened != 0 {
+		t.Errorf("expected no connection to be opened, but got: %+v", stats)
+	}
+}
@@ -1,6 +1,7 @@
 package sqlmock
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"fmt"
@@ -29,15 +30,14 @@ type mockDriver struct {
 
 func (d *mockDriver) Open(dsn string) (driver.Conn, error) {
 	d.Lock()
-	defer d.Unlock()
-
 	c, ok := d.connMap[dsn]
+	d.Unlock()
+
 	if !ok {
-		return c, fmt.Errorf("expected a connection to be available, but it is not")
+		return nil, fmt.Errorf("expected a connection to be available, but it is not")
 	}
 
-	c.opened++
-	return c, nil
+	return c.dial(context.Background())
 }
 
 // New creates sqlmock database connection and a mock to manage expectations.
@@ -122,7 +122,12 @@ func TestNewDSN(t *testing.T) {
 }
 
 func TestDuplicateNewDSN(t *testing.T) {
-	if _, _, err := NewWithDSN("sqlmock_db_1"); err == nil {
+	// the dsn must be held open by this test, a closed mock unregisters
+	// its dsn, so a fixed name such as "sqlmock_db_1" depends on which
+	// test happened to open it first and whether it was closed since
+	db, mock, _ := New()
+	defer db.Close()
+	if _, _, err := NewWithDSN(mock.(*sqlmock).dsn); err == nil {
 		t.Error("expected error on NewWithDSN")
 	}
 }
@@ -0,0 +1,266 @@
+package sqlmock
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Sentinel errors identifying the failures of the mock, the returned
+// errors carry the details of the failure and match these sentinels
+// with errors.Is.
+var (
+	// ErrUnexpectedCall is matched by errors returned for a call
+	// which does not match the pending expectations.
+	ErrUnexpectedCall = errors.New("sqlmock: unexpected call")
+
+	// ErrWrongConnection is matched by errors returned for a call expected
+	// with OnConn, but made on another connection.
+	ErrWrongConnection = errors.New("sqlmock: call made on another connection")
+
+	// ErrWrongTx is matched by errors returned for a call expected in an
+	// ExpectTx group, but made outside of the transaction of the group.
+	ErrWrongTx = errors.New("sqlmock: call made outside of the expected transaction")
+
+	// ErrWrongStatement is matched by errors returned for a call expected on
+	// a prepared statement, but not made through that statement.
+	ErrWrongStatement = errors.New("sqlmock: call not made on the expected prepared statement")
+
+	// ErrStmtClosed is matched by errors returned for a call
+	// made on a prepared statement after it was closed.
+	ErrStmtClosed = errors.New("sqlmock: prepared statement is closed")
+
+	// ErrUnsupportedIsolation is matched by errors returned for a transaction
+	// begun with an isolation level not set by SupportedIsolationLevelsOption.
+	ErrUnsupportedIsolation = errors.New("sqlmock: unsupported isolation level")
+
+	// ErrOutParam is matched by errors returned for a matched call, when
+	// an expected out parameter cannot be assigned to its sql.Out argument.
+	ErrOutParam = errors.New("sqlmock: out parameter cannot be assigned")
+
+	// ErrNoResult is matched by errors returned for a matched call, when
+	// the expectation has no rows or result to return.
+	ErrNoResult = errors.New("sqlmock: no rows or result to return")
+
+	// ErrUnmetExpectations is matched by the *UnmetExpectationsError
+	// returned by ExpectationsWereMet when an expectation was not met.
+	ErrUnmetExpectations = errors.New("sqlmock: there are remaining expectations which were not matched")
+
+	// ErrNotClosed is matched by the *UnmetExpectationsError returned
+	// by ExpectationsWereMet when rows or a statement were not closed.
+	ErrNotClosed = errors.New("sqlmock: rows or prepared statement were not closed")
+)
+
+// mockError is a failure of the mock identified by a sentinel error,
+// its message is kept as detailed as before sentinels were introduced
+type mockError struct {
+	kind error
+	msg  string
+	err  error
+}
+
+func newError(kind error, format string, args ...interface{}) error {
+	return &mockError{kind: kind, msg: fmt.Sprintf(format, args...)}
+}
+
+// wrapError is like newError for a failure caused by err,
+// which the returned error unwraps to
+func wrapError(kind, err error, format string, args ...interface{}) error {
+	return &mockError{kind: kind, msg: fmt.Sprintf(format, args...), err: err}
+}
+
+func (e *mockError) Error() string {
+	return e.msg
+}
+
+func (e *mockError) Is(target error) bool {
+	return target == e.kind
+}
+
+func (e *mockError) Unwrap() error {
+	return e.err
+}
+
+// ArgsMismatchError is the reason a call did not match an
+// expectation because of its arguments.
+type ArgsMismatchError struct {
+	// Err is the failure of the argument check.
+	Err error
+}
+
+func (e *ArgsMismatchError) Error() string {
+	return "arguments do not match: " + e.Err.Error()
+}
+
+func (e *ArgsMismatchError) Unwrap() error {
+	return e.Err
+}
+
+// UnmetExpectation is an expectation which was not
+// triggered as many times as it was expected.
+type UnmetExpectation struct {
+	// Expectation is the expectation which was not met,
+	// such as an *ExpectedSql or an *ExpectedBegin.
+	Expectation fmt.Stringer
+
+	// Calls describes how many times the expectation was
+	// called, it is empty when it was never called.
+	Calls string
+}
+
+func (u UnmetExpectation) String() string {
+	if u.Calls != "" {
+		return fmt.Sprintf("(%s): %s", u.Calls, u.Expectation)
+	}
+	return u.Expectation.String()
+}
+
+// UnmetExpectationsError is returned by ExpectationsWereMet with every
+// unexpected call, every expectation which was not met and every
+// prepared statement or rows which were not closed, along with the
+// calls which did happen.
+type UnmetExpectationsError struct {
+	// Unexpected is the journal of the unexpected calls.
+	Unexpected []UnexpectedCall
+
+	Unmet []UnmetExpectation
+
+	// NotClosed holds a *StmtNotClosedError or a
+	// *RowsNotClosedError for everything left open.
+	NotClosed []error
+
+	// Called describes every expectation which was
+	// triggered and how many times it was called.
+	Called []string
+}
+
+// Problems returns a message for every unexpected call, followed by
+// a message for every unmet expectation and everything left open.
+func (e *UnmetExpectationsError) Problems() []string {
+	var problems []string
+	for _, call := range e.Unexpected {
+		problems = append(problems, call.String())
+	}
+	for _, u := range e.Unmet {
+		if u.Calls != "" {
+			problems = append(problems, "there is a remaining expectation which was not matched "+u.String())
+			continue
+		}
+		problems = append(problems, "there is a remaining expectation which was not matched: "+u.String())
+	}
+	for _, err := range e.NotClosed {
+		problems = append(problems, err.Error())
+	}
+	return problems
+}
+
+// Report returns the itemized problems followed
+// by a summary of the calls which did happen.
+func (e *UnmetExpectationsError) Report() string {
+	var b strings.Builder
+	problems := e.Problems()
+	switch len(problems) {
+	case 0:
+		b.WriteString("all expectations were met")
+	case 1:
+		b.WriteString("there is 1 problem with the expectations:")
+	default:
+		fmt.Fprintf(&b, "there are %d problems with the expectations:", len(problems))
+	}
+	for i, problem := range problems {
+		fmt.Fprintf(&b, "\n  %d) %s", i+1, strings.Replace(problem, "\n", "\n     ", -1))
+	}
+
+	if len(e.Called) == 0 {
+		b.WriteString("\nno expectation was called")
+		return b.String()
+	}
+	b.WriteString("\ncalls which did happen:")
+	for _, called := range e.Called {
+		b.WriteString("\n  - " + called)
+	}
+	return b.String()
+}
+
+// Error returns the message of a single problem
+// as is, or the Report of several problems.
+func (e *UnmetExpectationsError) Error() string {
+	if problems := e.Problems(); len(problems) == 1 {
+		return problems[0]
+	}
+	return e.Report()
+}
+
+func (e *UnmetExpectationsError) Is(target error) bool {
+	if target == ErrUnmetExpectations && len(e.Unmet) > 0 || target == ErrNotClosed && len(e.NotClosed) > 0 {
+		return true
+	}
+	for _, call := range e.Unexpected {
+		if errors.Is(call.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the error of an unexpected call, a *StmtNotClosedError
+// or a *RowsNotClosedError among the reported errors.
+func (e *UnmetExpectationsError) As(target interface{}) bool {
+	for _, call := range e.Unexpected {
+		if errors.As(call.Err, target) {
+			return true
+		}
+	}
+	for _, err := range e.NotClosed {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// RowsNotClosedError reports the rows of a query expected
+// with RowsWillBeClosed, which were not closed.
+type RowsNotClosedError struct {
+	Expectation *ExpectedSql
+
+	// Call is the number of the call which returned the rows,
+	// counted from 1 in the order the query was called.
+	Call int
+
+	calls int
+}
+
+func (e *RowsNotClosedError) Error() string {
+	if e.calls > 1 {
+		return fmt.Sprintf("expected query rows returned by call %d of %d to be closed, but it was not: %s", e.Call, e.calls, e.Expectation)
+	}
+	return fmt.Sprintf("expected query rows to be closed, but it was not: %s", e.Expectation)
+}
+
+func (e *RowsNotClosedError) Is(target error) bool {
+	return target == ErrNotClosed
+}
+
+// StmtNotClosedError reports a prepared statement expected
+// with WillBeClosed, which was not closed.
+type StmtNotClosedError struct {
+	Expectation *ExpectedPrepare
+
+	// Call is the number of the call which prepared the statement,
+	// counted from 1 in the order the statement was prepared.
+	Call int
+
+	calls int
+}
+
+func (e *StmtNotClosedError) Error() string {
+	if e.calls > 1 {
+		return fmt.Sprintf("expected prepared statement %d of %d to be closed, but it was not: %s", e.Call, e.calls, e.Expectation)
+	}
+	return fmt.Sprintf("expected prepared statement to be closed, but it was not: %s", e.Expectation)
+}
+
+func (e *StmtNotClosedError) Is(target error) bool {
+	return target == ErrNotClosed
+}
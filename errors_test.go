@@ -0,0 +1,310 @@
+//go:build go1.8
+// +build go1.8
+
+package sqlmock
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestErrUnexpectedCall(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Begin(); !errors.Is(err, ErrUnexpectedCall) {
+		t.Fatalf("expected an unexpected call error on Begin, but got: %v", err)
+	}
+	_, err = db.Exec("DELETE FROM users")
+	if !errors.Is(err, ErrUnexpectedCall) {
+		t.Fatalf("expected an unexpected call error on Exec, but got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "was not expected") {
+		t.Errorf("expected the message to describe the call, but got: %s", err)
+	}
+
+	mock.ExpectBegin()
+	if _, err := db.Query("SELECT 1"); !errors.Is(err, ErrUnexpectedCall) {
+		t.Fatalf("expected an unexpected call error on Query, but got: %v", err)
+	}
+}
+
+func TestArgsMismatchError(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("DELETE FROM users").WithArgs(1).WillReturnResult(NewResult(0, 1))
+
+	_, err = db.Exec("DELETE FROM users", 2)
+	if !errors.Is(err, ErrUnexpectedCall) {
+		t.Fatalf("expected an unexpected call error, but got: %v", err)
+	}
+	var argsErr *ArgsMismatchError
+	if !errors.As(err, &argsErr) {
+		t.Fatalf("expected an *ArgsMismatchError, but got: %T", err)
+	}
+	if !strings.Contains(argsErr.Err.Error(), "argument 0") {
+		t.Errorf("expected the failed argument check, but got: %s", argsErr.Err)
+	}
+
+	// a sql mismatch is not an argument mismatch
+	mock.ExpectExec("UPDATE users").WillReturnResult(NewResult(0, 1))
+	_, err = db.Exec("INSERT INTO users")
+	if errors.As(err, &argsErr) {
+		t.Errorf("did not expect an *ArgsMismatchError, but got: %s", err)
+	}
+}
+
+func TestWrongStatementAndClosedStatementErrors(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.MatchExpectationsInOrder(false)
+
+	prep := mock.ExpectPrepare("SELECT name FROM users")
+	prep.ExpectQuery().WillReturnRows(NewRows([]string{"name"}))
+
+	_, err = db.Query("SELECT name FROM users")
+	if !errors.Is(err, ErrWrongStatement) || !errors.Is(err, ErrUnexpectedCall) {
+		t.Fatalf("expected a wrong statement error, but got: %v", err)
+	}
+
+	stmt, err := (&conn{sqlmock: mock.(*sqlmock)}).Prepare("SELECT name FROM users")
+	if err != nil {
+		t.Fatal("unexpected error while preparing a statement:", err)
+	}
+	if err := stmt.Close(); err != nil {
+		t.Fatal("unexpected error while closing the statement:", err)
+	}
+
+	if _, err := stmt.Query(nil); !errors.Is(err, ErrStmtClosed) {
+		t.Fatalf("expected a closed statement error, but got: %v", err)
+	}
+}
+
+func TestUnmetExpectationsError(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE users").WillReturnResult(NewResult(0, 1)).Times(2)
+	mock.ExpectCommit()
+
+	if _, err := db.Begin(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err = mock.ExpectationsWereMet()
+	if !errors.Is(err, ErrUnmetExpectations) {
+		t.Fatalf("expected unmet expectations, but got: %v", err)
+	}
+
+	var unmetErr *UnmetExpectationsError
+	if !errors.As(err, &unmetErr) {
+		t.Fatalf("expected an *UnmetExpectationsError, but got: %T", err)
+	}
+	if len(unmetErr.Unmet) != 2 {
+		t.Fatalf("expected 2 unmet expectations, but got: %d", len(unmetErr.Unmet))
+	}
+	if _, ok := unmetErr.Unmet[0].Expectation.(*ExpectedSql); !ok {
+		t.Errorf("expected the exec to be unmet first, but got: %T", unmetErr.Unmet[0].Expectation)
+	}
+	if _, ok := unmetErr.Unmet[1].Expectation.(*ExpectedCommit); !ok {
+		t.Errorf("expected the commit to be unmet, but got: %T", unmetErr.Unmet[1].Expectation)
+	}
+	if !strings.HasPrefix(err.Error(), "there are 2 problems with the expectations:") {
+		t.Errorf("unexpected message: %s", err)
+	}
+}
+
+func TestRowsNotClosedError(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT name FROM users").
+		WillReturnRows(NewRows([]string{"name"}).AddRow("john")).
+		RowsWillBeClosed()
+
+	rows, err := db.Query("SELECT name FROM users")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	rows.Next()
+
+	err = mock.ExpectationsWereMet()
+	var notClosed *RowsNotClosedError
+	if !errors.As(err, &notClosed) || !errors.Is(err, ErrNotClosed) {
+		t.Fatalf("expected a *RowsNotClosedError, but got: %v", err)
+	}
+	if notClosed.Expectation.expectSQL != "SELECT name FROM users" {
+		t.Errorf("unexpected expectation: %s", notClosed.Expectation)
+	}
+	rows.Close()
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestEachOneLeftOpenIsReported(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.MatchExpectationsInOrder(false)
+
+	mock.ExpectPrepare("SELECT name FROM users").WillBeClosed().Times(2)
+	mock.ExpectQuery("SELECT id FROM users").
+		WillReturnRows(NewRows([]string{"id"}).AddRow(1)).
+		RowsWillBeClosed().
+		Times(2)
+
+	stmt1, err := db.Prepare("SELECT name FROM users")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	stmt1.Close()
+	if _, err := db.Prepare("SELECT name FROM users"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	rows1, err := db.Query("SELECT id FROM users")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer rows1.Close()
+	rows1.Next()
+	rows2, err := db.Query("SELECT id FROM users")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	rows2.Close()
+
+	err = mock.ExpectationsWereMet()
+	var unmetErr *UnmetExpectationsError
+	if !errors.As(err, &unmetErr) || len(unmetErr.NotClosed) != 2 {
+		t.Fatalf("expected the leaked statement and rows to be reported, but got: %v", err)
+	}
+	stmtErr, ok := unmetErr.NotClosed[0].(*StmtNotClosedError)
+	if !ok || stmtErr.Call != 2 {
+		t.Errorf("expected the second statement to be left open, but got: %v", unmetErr.NotClosed[0])
+	}
+	rowsErr, ok := unmetErr.NotClosed[1].(*RowsNotClosedError)
+	if !ok || rowsErr.Call != 1 {
+		t.Errorf("expected the rows of the first call to be left open, but got: %v", unmetErr.NotClosed[1])
+	}
+	if !strings.HasPrefix(rowsErr.Error(), "expected query rows returned by call 1 of 2 to be closed, but it was not: ExpectedQuery") {
+		t.Errorf("unexpected message: %s", rowsErr)
+	}
+}
+
+func TestExpectationsWereMetReportsEveryProblem(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectPrepare("SELECT name FROM users").WillBeClosed()
+	mock.ExpectQuery("SELECT id FROM users").
+		WillReturnRows(NewRows([]string{"id"}).AddRow(1)).
+		RowsWillBeClosed()
+	mock.ExpectExec("UPDATE users").WillReturnResult(NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM users").WillReturnResult(NewResult(0, 1))
+
+	if _, err := db.Prepare("SELECT name FROM users"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	rows, err := db.Query("SELECT id FROM users")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer rows.Close()
+	rows.Next()
+
+	problems := mock.UnmetExpectations()
+	if len(problems) != 4 {
+		t.Fatalf("expected 4 problems, but got %d: %q", len(problems), problems)
+	}
+	for i, want := range []string{
+		"there is a remaining expectation which was not matched: ExpectedExec => expecting Exec or ExecContext which:\n  - matches sql: 'UPDATE users'",
+		"there is a remaining expectation which was not matched: ExpectedExec => expecting Exec or ExecContext which:\n  - matches sql: 'DELETE FROM users'",
+		"expected prepared statement to be closed, but it was not: ExpectedPrepare",
+		"expected query rows to be closed, but it was not: ExpectedQuery",
+	} {
+		if !strings.HasPrefix(problems[i], want) {
+			t.Errorf("expected problem %d to start with %q, but got: %s", i, want, problems[i])
+		}
+	}
+
+	err = mock.ExpectationsWereMet()
+	if !errors.Is(err, ErrUnmetExpectations) || !errors.Is(err, ErrNotClosed) {
+		t.Fatalf("expected unmet and not closed errors, but got: %v", err)
+	}
+	var stmtErr *StmtNotClosedError
+	var rowsErr *RowsNotClosedError
+	if !errors.As(err, &stmtErr) || !errors.As(err, &rowsErr) {
+		t.Errorf("expected to find the not closed errors in: %v", err)
+	}
+	if err.Error() != mock.Report() {
+		t.Errorf("expected the error to be the report, but got: %s", err)
+	}
+
+	report := mock.Report()
+	for _, want := range []string{
+		"there are 4 problems with the expectations:",
+		"\n  1) there is a remaining expectation",
+		"\n  4) expected query rows to be closed",
+		"\ncalls which did happen:",
+		"\n  - ExpectedPrepare => expecting Prepare statement which: matches sql: 'SELECT name FROM users' (called 1 time)",
+		"\n  - ExpectedQuery => expecting Query, QueryContext or QueryRow which: matches sql: 'SELECT id FROM users' (called 1 time)",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("expected the report to contain %q, but got:\n%s", want, report)
+		}
+	}
+}
+
+func TestReportWhenExpectationsWereMet(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	if report := mock.Report(); report != "all expectations were met\nno expectation was called" {
+		t.Errorf("unexpected report: %s", report)
+	}
+
+	mock.ExpectBegin()
+	if _, err := db.Begin(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+	if problems := mock.UnmetExpectations(); len(problems) != 0 {
+		t.Errorf("expected no problems, but got: %q", problems)
+	}
+	want := "all expectations were met\ncalls which did happen:\n  - ExpectedBegin => expecting database transaction Begin (called 1 time)"
+	if report := mock.Report(); report != want {
+		t.Errorf("expected report:\n%s\nbut got:\n%s", want, report)
+	}
+}
@@ -1,6 +1,8 @@
 package sqlmock
 
 import (
+	"context"
+	"database/sql"
 	"database/sql/driver"
 	"fmt"
 	"strings"
@@ -11,6 +13,9 @@ import (
 // an expectation interface
 type expectation interface {
 	fulfilled() bool
+	exhausted() bool
+	describeCalls() string
+	triggeredTimes() int
 	Lock()
 	Unlock()
 	String() string
@@ -20,14 +25,130 @@ type expectation interface {
 // satisfies the expectation interface
 type commonExpectation struct {
 	sync.Mutex
-	triggered bool
+	triggered int
+	optional  bool
 	err       error
+
+	// group is the transaction this expectation was declared in
+	// by ExpectTx and txs records the transaction each call ran in
+	group *ExpectedTx
+	txs   []*mockTx
+
+	// pinned expectations must be triggered on
+	// the connection opened at position connIndex
+	pinned    bool
+	connIndex int
+
+	// call count bounds, only used once timesSet is true,
+	// otherwise an expectation must be triggered exactly once.
+	// maxTimes below zero means there is no upper bound
+	timesSet bool
+	minTimes int
+	maxTimes int
+}
+
+// bounds returns the minimum and maximum number of calls
+// this expectation accepts
+func (e *commonExpectation) bounds() (min, max int) {
+	if !e.timesSet {
+		return 1, 1
+	}
+	return e.minTimes, e.maxTimes
 }
 
 func (e *commonExpectation) fulfilled() bool {
+	min, _ := e.bounds()
+	return e.optional || e.triggered >= min
+}
+
+// exhausted reports whether the expectation
+// cannot be matched by any further call
+func (e *commonExpectation) exhausted() bool {
+	_, max := e.bounds()
+	return max >= 0 && e.triggered >= max
+}
+
+func (e *commonExpectation) times(n int) {
+	e.timesSet, e.minTimes, e.maxTimes = true, n, n
+}
+
+// atLeast keeps an upper bound set before, unless it is below n,
+// otherwise the expectation may be called any number of times
+func (e *commonExpectation) atLeast(n int) {
+	max := e.maxTimes
+	if !e.timesSet || (max >= 0 && max < n) {
+		max = -1
+	}
+	e.timesSet, e.minTimes, e.maxTimes = true, n, max
+}
+
+// atMost keeps a lower bound set before, unless it is above n,
+// otherwise the expectation may not be called at all
+func (e *commonExpectation) atMost(n int) {
+	min := e.minTimes
+	if !e.timesSet {
+		min = 0
+	} else if min > n {
+		min = n
+	}
+	e.timesSet, e.minTimes, e.maxTimes = true, min, n
+}
+
+func (e *commonExpectation) anyTimes() {
+	e.timesSet, e.minTimes, e.maxTimes = true, 0, -1
+}
+
+// describeTxs lists the transaction each call ran in, empty
+// string if none of the calls ran inside of a transaction
+func (e *commonExpectation) describeTxs() string {
+	var inTx bool
+	ids := make([]string, len(e.txs))
+	for i, tx := range e.txs {
+		ids[i] = "none"
+		if tx != nil {
+			ids[i] = tx.String()
+			inTx = true
+		}
+	}
+	if !inTx {
+		return ""
+	}
+	return "was called in transactions: " + strings.Join(ids, ", ")
+}
+
+// describeCalls returns the actual vs. expected call count
+// for expectations with custom bounds, empty string otherwise
+func (e *commonExpectation) describeCalls() string {
+	if !e.timesSet {
+		return ""
+	}
+
+	min, max := e.bounds()
+	var want string
+	switch {
+	case min == max:
+		want = pluralTimes(min)
+	case max < 0:
+		want = "at least " + pluralTimes(min)
+	case min == 0:
+		want = "at most " + pluralTimes(max)
+	default:
+		want = fmt.Sprintf("between %d and %d times", min, max)
+	}
+	return fmt.Sprintf("called %s, expected %s", pluralTimes(e.triggered), want)
+}
+
+func (e *commonExpectation) triggeredTimes() int {
 	return e.triggered
 }
 
+func pluralTimes(n int) string {
+	if n == 1 {
+		return "1 time"
+	}
+	return fmt.Sprintf("%d times", n)
+}
+
 // ExpectedClose is used to manage *sql.DB.Close expectation
 // returned by *Sqlmock.ExpectClose.
 type ExpectedClose struct {
@@ -40,6 +161,20 @@ func (e *ExpectedClose) WillReturnError(err error) *ExpectedClose {
 	return e
 }
 
+// Maybe marks the database Close as optional, it may be matched
+// but ExpectationsWereMet does not require it to be.
+func (e *ExpectedClose) Maybe() *ExpectedClose {
+	e.optional = true
+	return e
+}
+
+// OnConn expects the database Close to be called on the n-th
+// connection opened for the mock database, counted from zero.
+func (e *ExpectedClose) OnConn(n int) *ExpectedClose {
+	e.pinned, e.connIndex = true, n
+	return e
+}
+
 // String returns string representation
 func (e *ExpectedClose) String() string {
 	msg := "ExpectedClose => expecting database Close"
@@ -49,11 +184,76 @@ func (e *ExpectedClose) String() string {
 	return msg
 }
 
+// ExpectedConnect is used to manage connections opened by the driver,
+// returned by *Sqlmock.ExpectConnect.
+type ExpectedConnect struct {
+	commonExpectation
+	delay time.Duration
+}
+
+// WillReturnError allows to set an error for the connection attempt,
+// driver.ErrBadConn makes database/sql retry with another connection
+func (e *ExpectedConnect) WillReturnError(err error) *ExpectedConnect {
+	e.err = err
+	return e
+}
+
+// WillDelayFor allows to specify duration for which it will delay
+// the connection attempt. May be used together with Context
+func (e *ExpectedConnect) WillDelayFor(duration time.Duration) *ExpectedConnect {
+	e.delay = duration
+	return e
+}
+
+// Times expects the connection to be opened exactly n times.
+func (e *ExpectedConnect) Times(n int) *ExpectedConnect {
+	e.times(n)
+	return e
+}
+
+// AtLeast expects the connection to be opened n times or more.
+func (e *ExpectedConnect) AtLeast(n int) *ExpectedConnect {
+	e.atLeast(n)
+	return e
+}
+
+// AtMost allows the connection to be opened up to n times.
+func (e *ExpectedConnect) AtMost(n int) *ExpectedConnect {
+	e.atMost(n)
+	return e
+}
+
+// AnyTimes allows the connection to be opened any number
+// of times, including none at all.
+func (e *ExpectedConnect) AnyTimes() *ExpectedConnect {
+	e.anyTimes()
+	return e
+}
+
+// Maybe marks the connection attempt as optional, it may be matched
+// but ExpectationsWereMet does not require it to be.
+func (e *ExpectedConnect) Maybe() *ExpectedConnect {
+	e.optional = true
+	return e
+}
+
+// String returns string representation
+func (e *ExpectedConnect) String() string {
+	msg := "ExpectedConnect => expecting database connection to be opened"
+	if e.err != nil {
+		msg += fmt.Sprintf(", which should return error: %s", e.err)
+	}
+	return msg
+}
+
 // ExpectedBegin is used to manage *sql.DB.Begin expectation
 // returned by *Sqlmock.ExpectBegin.
 type ExpectedBegin struct {
 	commonExpectation
-	delay time.Duration
+	delay     time.Duration
+	isolation *driver.IsolationLevel
+	readOnly  *bool
+	checkOpts func(opts driver.TxOptions) error
 }
 
 // WillReturnError allows to set an error for *sql.DB.Begin action
@@ -62,9 +262,54 @@ func (e *ExpectedBegin) WillReturnError(err error) *ExpectedBegin {
 	return e
 }
 
+// Times expects the transaction Begin to be called exactly n times.
+func (e *ExpectedBegin) Times(n int) *ExpectedBegin {
+	e.times(n)
+	return e
+}
+
+// AtLeast expects the transaction Begin to be called n times or more.
+func (e *ExpectedBegin) AtLeast(n int) *ExpectedBegin {
+	e.atLeast(n)
+	return e
+}
+
+// AtMost allows the transaction Begin to be called up to n times.
+func (e *ExpectedBegin) AtMost(n int) *ExpectedBegin {
+	e.atMost(n)
+	return e
+}
+
+// AnyTimes allows the transaction Begin to be called any number
+// of times, including none at all.
+func (e *ExpectedBegin) AnyTimes() *ExpectedBegin {
+	e.anyTimes()
+	return e
+}
+
+// Maybe marks the transaction Begin as optional, it may be matched
+// but ExpectationsWereMet does not require it to be.
+func (e *ExpectedBegin) Maybe() *ExpectedBegin {
+	e.optional = true
+	return e
+}
+
+// OnConn expects the transaction Begin to be called on the n-th
+// connection opened for the mock database, counted from zero.
+func (e *ExpectedBegin) OnConn(n int) *ExpectedBegin {
+	e.pinned, e.connIndex = true, n
+	return e
+}
+
 // String returns string representation
 func (e *ExpectedBegin) String() string {
 	msg := "ExpectedBegin => expecting database transaction Begin"
+	if e.isolation != nil {
+		msg += fmt.Sprintf(", with isolation level %s", sql.IsolationLevel(*e.isolation))
+	}
+	if e.readOnly != nil {
+		msg += fmt.Sprintf(", with read-only %t", *e.readOnly)
+	}
 	if e.err != nil {
 		msg += fmt.Sprintf(", which should return error: %s", e.err)
 	}
@@ -78,6 +323,42 @@ func (e *ExpectedBegin) WillDelayFor(duration time.Duration) *ExpectedBegin {
 	return e
 }
 
+// WithIsolation expects the transaction to be started
+// with the given isolation level by *sql.DB.BeginTx.
+func (e *ExpectedBegin) WithIsolation(level sql.IsolationLevel) *ExpectedBegin {
+	isolation := driver.IsolationLevel(level)
+	e.isolation = &isolation
+	return e
+}
+
+// WithReadOnly expects the transaction to be started
+// as read-only or not by *sql.DB.BeginTx.
+func (e *ExpectedBegin) WithReadOnly(readOnly bool) *ExpectedBegin {
+	e.readOnly = &readOnly
+	return e
+}
+
+// WithTxOptionsCheck allows to verify the options passed to BeginTx
+// with a custom function, an error returned fails the Begin call.
+func (e *ExpectedBegin) WithTxOptionsCheck(checkOpts func(opts driver.TxOptions) error) *ExpectedBegin {
+	e.checkOpts = checkOpts
+	return e
+}
+
+// checkTxOptions verifies the options the transaction is started with
+func (e *ExpectedBegin) checkTxOptions(opts driver.TxOptions) error {
+	if e.isolation != nil && *e.isolation != opts.Isolation {
+		return fmt.Errorf("expected transaction isolation level %s, but got %s", sql.IsolationLevel(*e.isolation), sql.IsolationLevel(opts.Isolation))
+	}
+	if e.readOnly != nil && *e.readOnly != opts.ReadOnly {
+		return fmt.Errorf("expected transaction read-only to be %t, but got %t", *e.readOnly, opts.ReadOnly)
+	}
+	if e.checkOpts != nil {
+		return e.checkOpts(opts)
+	}
+	return nil
+}
+
 // ExpectedCommit is used to manage *sql.Tx.Commit expectation
 // returned by *Sqlmock.ExpectCommit.
 type ExpectedCommit struct {
@@ -90,6 +371,45 @@ func (e *ExpectedCommit) WillReturnError(err error) *ExpectedCommit {
 	return e
 }
 
+// Times expects the transaction Commit to be called exactly n times.
+func (e *ExpectedCommit) Times(n int) *ExpectedCommit {
+	e.times(n)
+	return e
+}
+
+// AtLeast expects the transaction Commit to be called n times or more.
+func (e *ExpectedCommit) AtLeast(n int) *ExpectedCommit {
+	e.atLeast(n)
+	return e
+}
+
+// AtMost allows the transaction Commit to be called up to n times.
+func (e *ExpectedCommit) AtMost(n int) *ExpectedCommit {
+	e.atMost(n)
+	return e
+}
+
+// AnyTimes allows the transaction Commit to be called any number
+// of times, including none at all.
+func (e *ExpectedCommit) AnyTimes() *ExpectedCommit {
+	e.anyTimes()
+	return e
+}
+
+// Maybe marks the transaction Commit as optional, it may be matched
+// but ExpectationsWereMet does not require it to be.
+func (e *ExpectedCommit) Maybe() *ExpectedCommit {
+	e.optional = true
+	return e
+}
+
+// OnConn expects the transaction Commit to be called on the n-th
+// connection opened for the mock database, counted from zero.
+func (e *ExpectedCommit) OnConn(n int) *ExpectedCommit {
+	e.pinned, e.connIndex = true, n
+	return e
+}
+
 // String returns string representation
 func (e *ExpectedCommit) String() string {
 	msg := "ExpectedCommit => expecting transaction Commit"
@@ -111,6 +431,45 @@ func (e *ExpectedRollback) WillReturnError(err error) *ExpectedRollback {
 	return e
 }
 
+// Times expects the transaction Rollback to be called exactly n times.
+func (e *ExpectedRollback) Times(n int) *ExpectedRollback {
+	e.times(n)
+	return e
+}
+
+// AtLeast expects the transaction Rollback to be called n times or more.
+func (e *ExpectedRollback) AtLeast(n int) *ExpectedRollback {
+	e.atLeast(n)
+	return e
+}
+
+// AtMost allows the transaction Rollback to be called up to n times.
+func (e *ExpectedRollback) AtMost(n int) *ExpectedRollback {
+	e.atMost(n)
+	return e
+}
+
+// AnyTimes allows the transaction Rollback to be called any number
+// of times, including none at all.
+func (e *ExpectedRollback) AnyTimes() *ExpectedRollback {
+	e.anyTimes()
+	return e
+}
+
+// Maybe marks the transaction Rollback as optional, it may be matched
+// but ExpectationsWereMet does not require it to be.
+func (e *ExpectedRollback) Maybe() *ExpectedRollback {
+	e.optional = true
+	return e
+}
+
+// OnConn expects the transaction Rollback to be called on the n-th
+// connection opened for the mock database, counted from zero.
+func (e *ExpectedRollback) OnConn(n int) *ExpectedRollback {
+	e.pinned, e.connIndex = true, n
+	return e
+}
+
 // String returns string representation
 func (e *ExpectedRollback) String() string {
 	msg := "ExpectedRollback => expecting transaction Rollback"
@@ -120,17 +479,23 @@ func (e *ExpectedRollback) String() string {
 	return msg
 }
 
-// ExpectedSql is used to manage *sql.DB.Query, *dql.DB.QueryRow, *sql.Tx.Query,
-// *sql.Tx.QueryRow, *sql.Stmt.Query or *sql.Stmt.QueryRow expectations.
-// Returned by *Sqlmock.ExpectQuery.
+// ExpectedSql is used to manage any query or exec expectation, the
+// operation is matched by the expectedOpt matcher against "query"
+// or "exec". Returned by *Sqlmock.ExpectSql.
 type ExpectedSql struct {
 	queryBasedExpectation
-	rows             driver.Rows
+	op               string
+	prepared         *ExpectedPrepare
+	rows             *rowSets
 	delay            time.Duration
 	rowsMustBeClosed bool
-	rowsWereClosed   bool
+	returnedRows     []*rowSets
 	result           driver.Result
+	outParams        map[string]interface{}
+	outOrdinals      map[int]interface{}
+	calls            []Call
 	expectedOpt      Matcher
+	respond          func(ctx context.Context, query string, args []driver.NamedValue) (*Rows, driver.Result, error)
 }
 
 // WithArgsCheck match sql args
@@ -147,6 +512,14 @@ func (e *ExpectedSql) WithArgs(args ...driver.Value) *ExpectedSql {
 	return e
 }
 
+// WithNamedArgs will match the named arguments of the actual call by name,
+// regardless of their position, values may be an sqlmock.Matcher. Positional
+// arguments set by WithArgs are then matched apart, in their relative order.
+func (e *ExpectedSql) WithNamedArgs(args map[string]driver.Value) *ExpectedSql {
+	e.namedArgs = args
+	return e
+}
+
 // RowsWillBeClosed expects this query rows to be closed.
 func (e *ExpectedSql) RowsWillBeClosed() *ExpectedSql {
 	e.rowsMustBeClosed = true
@@ -178,14 +551,64 @@ func (e *ExpectedSql) WillReturnRows(rows ...*Rows) *ExpectedSql {
 	return e
 }
 
+// Times expects the query to be called exactly n times.
+func (e *ExpectedSql) Times(n int) *ExpectedSql {
+	e.times(n)
+	return e
+}
+
+// AtLeast expects the query to be called n times or more.
+func (e *ExpectedSql) AtLeast(n int) *ExpectedSql {
+	e.atLeast(n)
+	return e
+}
+
+// AtMost allows the query to be called up to n times.
+func (e *ExpectedSql) AtMost(n int) *ExpectedSql {
+	e.atMost(n)
+	return e
+}
+
+// AnyTimes allows the query to be called any number
+// of times, including none at all.
+func (e *ExpectedSql) AnyTimes() *ExpectedSql {
+	e.anyTimes()
+	return e
+}
+
+// Maybe marks the query as optional, it may be matched
+// but ExpectationsWereMet does not require it to be.
+func (e *ExpectedSql) Maybe() *ExpectedSql {
+	e.optional = true
+	return e
+}
+
+// OnConn expects the query to be called on the n-th
+// connection opened for the mock database, counted from zero.
+func (e *ExpectedSql) OnConn(n int) *ExpectedSql {
+	e.pinned, e.connIndex = true, n
+	return e
+}
+
+// matchesOp checks whether the operation, "query" or "exec", is expected
+func (e *ExpectedSql) matchesOp(opt string) bool {
+	return e.expectedOpt == nil || e.expectedOpt.Match(opt)
+}
+
 // String returns string representation
 func (e *ExpectedSql) String() string {
 	msg := "ExpectedSql => expecting Query, QueryContext or QueryRow which:"
+	switch e.op {
+	case "query":
+		msg = "ExpectedQuery => expecting Query, QueryContext or QueryRow which:"
+	case "exec":
+		msg = "ExpectedExec => expecting Exec or ExecContext which:"
+	}
 	msg += "\n  - matches sql: '" + e.expectSQL + "'"
 
-	if len(e.args) == 0 {
+	if len(e.args) == 0 && len(e.namedArgs) == 0 {
 		msg += "\n  - is without arguments"
-	} else {
+	} else if len(e.args) > 0 {
 		msg += "\n  - is with arguments:\n"
 		for i, arg := range e.args {
 			msg += fmt.Sprintf("    %d - %+v\n", i, arg)
@@ -193,10 +616,38 @@ func (e *ExpectedSql) String() string {
 		msg = strings.TrimSpace(msg)
 	}
 
+	if len(e.namedArgs) > 0 {
+		msg += "\n  - is with named arguments:\n"
+		for _, name := range sortedNames(e.namedArgs) {
+			msg += fmt.Sprintf("    :%s - %+v\n", name, e.namedArgs[name])
+		}
+		msg = strings.TrimSpace(msg)
+	}
+
 	if e.rows != nil {
 		msg += fmt.Sprintf("\n  - %s", e.rows)
 	}
 
+	if e.result != nil {
+		msg += "\n  - should return Result"
+	}
+
+	if params := e.describeOutParams(); params != "" {
+		msg += "\n  - should return out parameters: " + params
+	}
+
+	if e.respond != nil {
+		msg += "\n  - should respond with a computed result"
+	}
+
+	if e.prepared != nil {
+		msg += "\n  - should run through the statement prepared for: '" + e.prepared.expectSQL + "'"
+	}
+
+	if txs := e.describeTxs(); txs != "" {
+		msg += "\n  - " + txs
+	}
+
 	if e.err != nil {
 		msg += fmt.Sprintf("\n  - should return error: %s", e.err)
 	}
@@ -204,6 +655,197 @@ func (e *ExpectedSql) String() string {
 	return msg
 }
 
+// ExpectedQuery is used to manage *sql.DB.Query, *dql.DB.QueryRow, *sql.Tx.Query,
+// *sql.Tx.QueryRow, *sql.Stmt.Query or *sql.Stmt.QueryRow expectations.
+// Returned by *Sqlmock.ExpectQuery.
+type ExpectedQuery struct {
+	ex *ExpectedSql
+}
+
+// WithArgs will match given expected args to actual database query arguments.
+// if at least one argument does not match, it will return an error. For specific
+// arguments an sqlmock.Matcher interface can be used to match an argument.
+func (e *ExpectedQuery) WithArgs(args ...driver.Value) *ExpectedQuery {
+	e.ex.WithArgs(args...)
+	return e
+}
+
+// WithArgsCheck match sql args
+func (e *ExpectedQuery) WithArgsCheck(checkArgs func(args []driver.Value) error) *ExpectedQuery {
+	e.ex.WithArgsCheck(checkArgs)
+	return e
+}
+
+// WithNamedArgs will match the named arguments of the actual query by name,
+// regardless of their position, values may be an sqlmock.Matcher.
+func (e *ExpectedQuery) WithNamedArgs(args map[string]driver.Value) *ExpectedQuery {
+	e.ex.WithNamedArgs(args)
+	return e
+}
+
+// RowsWillBeClosed expects this query rows to be closed.
+func (e *ExpectedQuery) RowsWillBeClosed() *ExpectedQuery {
+	e.ex.RowsWillBeClosed()
+	return e
+}
+
+// WillReturnRows specifies the set of resulting rows that will be returned
+// by the triggered query, one Rows for every result set.
+func (e *ExpectedQuery) WillReturnRows(rows ...*Rows) *ExpectedQuery {
+	e.ex.WillReturnRows(rows...)
+	return e
+}
+
+// WillReturnError allows to set an error for expected database query
+func (e *ExpectedQuery) WillReturnError(err error) *ExpectedQuery {
+	e.ex.WillReturnError(err)
+	return e
+}
+
+// WillDelayFor allows to specify duration for which it will delay
+// result. May be used together with Context
+func (e *ExpectedQuery) WillDelayFor(duration time.Duration) *ExpectedQuery {
+	e.ex.WillDelayFor(duration)
+	return e
+}
+
+// Times expects the query to be called exactly n times.
+func (e *ExpectedQuery) Times(n int) *ExpectedQuery {
+	e.ex.Times(n)
+	return e
+}
+
+// AtLeast expects the query to be called n times or more.
+func (e *ExpectedQuery) AtLeast(n int) *ExpectedQuery {
+	e.ex.AtLeast(n)
+	return e
+}
+
+// AtMost allows the query to be called up to n times.
+func (e *ExpectedQuery) AtMost(n int) *ExpectedQuery {
+	e.ex.AtMost(n)
+	return e
+}
+
+// AnyTimes allows the query to be called any number
+// of times, including none at all.
+func (e *ExpectedQuery) AnyTimes() *ExpectedQuery {
+	e.ex.AnyTimes()
+	return e
+}
+
+// Maybe marks the query as optional, it may be matched
+// but ExpectationsWereMet does not require it to be.
+func (e *ExpectedQuery) Maybe() *ExpectedQuery {
+	e.ex.Maybe()
+	return e
+}
+
+// OnConn expects the query to be called on the n-th
+// connection opened for the mock database, counted from zero.
+func (e *ExpectedQuery) OnConn(n int) *ExpectedQuery {
+	e.ex.OnConn(n)
+	return e
+}
+
+// String returns string representation
+func (e *ExpectedQuery) String() string {
+	return e.ex.String()
+}
+
+// ExpectedExec is used to manage *sql.DB.Exec, *sql.Tx.Exec or *sql.Stmt.Exec expectations.
+// Returned by *Sqlmock.ExpectExec.
+type ExpectedExec struct {
+	ex *ExpectedSql
+}
+
+// WithArgs will match given expected args to actual database exec operation arguments.
+// if at least one argument does not match, it will return an error. For specific
+// arguments an sqlmock.Matcher interface can be used to match an argument.
+func (e *ExpectedExec) WithArgs(args ...driver.Value) *ExpectedExec {
+	e.ex.WithArgs(args...)
+	return e
+}
+
+// WithArgsCheck match sql args
+func (e *ExpectedExec) WithArgsCheck(checkArgs func(args []driver.Value) error) *ExpectedExec {
+	e.ex.WithArgsCheck(checkArgs)
+	return e
+}
+
+// WithNamedArgs will match the named arguments of the actual exec by name,
+// regardless of their position, values may be an sqlmock.Matcher.
+func (e *ExpectedExec) WithNamedArgs(args map[string]driver.Value) *ExpectedExec {
+	e.ex.WithNamedArgs(args)
+	return e
+}
+
+// WillReturnResult arranges for an expected Exec() to return a particular
+// result, there is sqlmock.NewResult(lastInsertID int64, affectedRows int64) method
+// to build a corresponding result. Or if actions needs to be tested against errors
+// sqlmock.NewErrorResult(err error) to return a given error.
+func (e *ExpectedExec) WillReturnResult(result driver.Result) *ExpectedExec {
+	e.ex.WillReturnResult(result)
+	return e
+}
+
+// WillReturnError allows to set an error for expected database exec action
+func (e *ExpectedExec) WillReturnError(err error) *ExpectedExec {
+	e.ex.WillReturnError(err)
+	return e
+}
+
+// WillDelayFor allows to specify duration for which it will delay
+// result. May be used together with Context
+func (e *ExpectedExec) WillDelayFor(duration time.Duration) *ExpectedExec {
+	e.ex.WillDelayFor(duration)
+	return e
+}
+
+// Times expects the exec to be called exactly n times.
+func (e *ExpectedExec) Times(n int) *ExpectedExec {
+	e.ex.Times(n)
+	return e
+}
+
+// AtLeast expects the exec to be called n times or more.
+func (e *ExpectedExec) AtLeast(n int) *ExpectedExec {
+	e.ex.AtLeast(n)
+	return e
+}
+
+// AtMost allows the exec to be called up to n times.
+func (e *ExpectedExec) AtMost(n int) *ExpectedExec {
+	e.ex.AtMost(n)
+	return e
+}
+
+// AnyTimes allows the exec to be called any number
+// of times, including none at all.
+func (e *ExpectedExec) AnyTimes() *ExpectedExec {
+	e.ex.AnyTimes()
+	return e
+}
+
+// Maybe marks the exec as optional, it may be matched
+// but ExpectationsWereMet does not require it to be.
+func (e *ExpectedExec) Maybe() *ExpectedExec {
+	e.ex.Maybe()
+	return e
+}
+
+// OnConn expects the exec to be called on the n-th
+// connection opened for the mock database, counted from zero.
+func (e *ExpectedExec) OnConn(n int) *ExpectedExec {
+	e.ex.OnConn(n)
+	return e
+}
+
+// String returns string representation
+func (e *ExpectedExec) String() string {
+	return e.ex.String()
+}
+
 // ExpectedPrepare is used to manage *sql.DB.Prepare or *sql.Tx.Prepare expectations.
 // Returned by *Sqlmock.WithPrepare.
 type ExpectedPrepare struct {
@@ -212,8 +854,11 @@ type ExpectedPrepare struct {
 	expectSQL    string
 	closeErr     error
 	mustBeClosed bool
-	wasClosed    bool
+	statements   []*statement
 	delay        time.Duration
+	numInput     int
+	numInputSet  bool
+	countInput   bool
 }
 
 // WillReturnError allows to set an error for the expected *sql.DB.Prepare or *sql.Tx.Prepare action.
@@ -242,6 +887,87 @@ func (e *ExpectedPrepare) WillBeClosed() *ExpectedPrepare {
 	return e
 }
 
+// WithNumInput sets the number of arguments the prepared statement takes,
+// database/sql then rejects any call to the statement with a different
+// number of arguments, exactly as it would with a real driver.
+func (e *ExpectedPrepare) WithNumInput(n int) *ExpectedPrepare {
+	e.numInput, e.numInputSet = n, true
+	return e
+}
+
+// WithNumInputFromSQL sets the number of arguments the prepared statement
+// takes by counting the "?", "$N", ":name" and "@name" placeholders
+// in the sql actually prepared.
+func (e *ExpectedPrepare) WithNumInputFromSQL() *ExpectedPrepare {
+	e.countInput = true
+	return e
+}
+
+// Times expects the Prepare to be called exactly n times.
+func (e *ExpectedPrepare) Times(n int) *ExpectedPrepare {
+	e.times(n)
+	return e
+}
+
+// AtLeast expects the Prepare to be called n times or more.
+func (e *ExpectedPrepare) AtLeast(n int) *ExpectedPrepare {
+	e.atLeast(n)
+	return e
+}
+
+// AtMost allows the Prepare to be called up to n times.
+func (e *ExpectedPrepare) AtMost(n int) *ExpectedPrepare {
+	e.atMost(n)
+	return e
+}
+
+// AnyTimes allows the Prepare to be called any number
+// of times, including none at all.
+func (e *ExpectedPrepare) AnyTimes() *ExpectedPrepare {
+	e.anyTimes()
+	return e
+}
+
+// Maybe marks the Prepare as optional, it may be matched
+// but ExpectationsWereMet does not require it to be.
+func (e *ExpectedPrepare) Maybe() *ExpectedPrepare {
+	e.optional = true
+	return e
+}
+
+// OnConn expects the Prepare to be called on the n-th
+// connection opened for the mock database, counted from zero.
+func (e *ExpectedPrepare) OnConn(n int) *ExpectedPrepare {
+	e.pinned, e.connIndex = true, n
+	return e
+}
+
+// ExpectSql allows to expect a Query or an Exec on this prepared statement,
+// the operation is matched with expectedOpt against "query" or "exec".
+// Calls with the same sql made outside of this statement do not match.
+func (e *ExpectedPrepare) ExpectSql(expectedOpt Matcher) *ExpectedSql {
+	ex := e.mock.ExpectSql(expectedOpt, e.expectSQL)
+	ex.prepared = e
+	ex.group = e.group
+	return ex
+}
+
+// ExpectQuery allows to expect Query() or QueryRow() on this prepared statement.
+// This method is convenient in order to prevent duplicating sql query string matching.
+func (e *ExpectedPrepare) ExpectQuery() *ExpectedQuery {
+	ex := e.ExpectSql(Query())
+	ex.op = "query"
+	return &ExpectedQuery{ex: ex}
+}
+
+// ExpectExec allows to expect Exec() on this prepared statement.
+// This method is convenient in order to prevent duplicating sql query string matching.
+func (e *ExpectedPrepare) ExpectExec() *ExpectedExec {
+	ex := e.ExpectSql(Exec())
+	ex.op = "exec"
+	return &ExpectedExec{ex: ex}
+}
+
 // String returns string representation
 func (e *ExpectedPrepare) String() string {
 	msg := "ExpectedPrepare => expecting Prepare statement which:"
@@ -255,9 +981,65 @@ func (e *ExpectedPrepare) String() string {
 		msg += fmt.Sprintf("\n  - should return error on Close: %s", e.closeErr)
 	}
 
+	if e.numInputSet {
+		msg += fmt.Sprintf("\n  - takes %d arguments", e.numInput)
+	}
+
+	if txs := e.describeTxs(); txs != "" {
+		msg += "\n  - " + txs
+	}
+
 	return msg
 }
 
+// ExpectedTx is used to manage a group of expectations, which
+// must be triggered inside of a single transaction.
+// Returned by *Sqlmock.ExpectTx.
+type ExpectedTx struct {
+	mock  *sqlmock
+	begin *ExpectedBegin
+	end   expectation
+}
+
+// Begin returns the expectation of the transaction Begin,
+// so that it can be configured further.
+func (e *ExpectedTx) Begin() *ExpectedBegin {
+	return e.begin
+}
+
+// WithIsolation expects the transaction to be started
+// with the given isolation level.
+func (e *ExpectedTx) WithIsolation(level sql.IsolationLevel) *ExpectedTx {
+	e.begin.WithIsolation(level)
+	return e
+}
+
+// WithReadOnly expects the transaction to be started
+// as read-only or not.
+func (e *ExpectedTx) WithReadOnly(readOnly bool) *ExpectedTx {
+	e.begin.WithReadOnly(readOnly)
+	return e
+}
+
+// WillRollback expects the transaction to be rolled back
+// instead of being committed.
+func (e *ExpectedTx) WillRollback() *ExpectedTx {
+	rollback := &ExpectedRollback{}
+	rollback.group = e
+	for i, next := range e.mock.expected {
+		if next == e.end {
+			e.mock.expected[i] = rollback
+		}
+	}
+	e.end = rollback
+	return e
+}
+
+// String returns string representation
+func (e *ExpectedTx) String() string {
+	return "transaction group started by " + e.begin.String()
+}
+
 // query based expectation
 // adds a query matching logic
 type queryBasedExpectation struct {
@@ -265,6 +1047,7 @@ type queryBasedExpectation struct {
 	expectSQL string
 	converter driver.ValueConverter
 	args      []driver.Value
+	namedArgs map[string]driver.Value
 	checkArgs func(args []driver.Value) error
 }
 
@@ -288,6 +1071,45 @@ func (e *ExpectedPing) WillReturnError(err error) *ExpectedPing {
 	return e
 }
 
+// Times expects the database Ping to be called exactly n times.
+func (e *ExpectedPing) Times(n int) *ExpectedPing {
+	e.times(n)
+	return e
+}
+
+// AtLeast expects the database Ping to be called n times or more.
+func (e *ExpectedPing) AtLeast(n int) *ExpectedPing {
+	e.atLeast(n)
+	return e
+}
+
+// AtMost allows the database Ping to be called up to n times.
+func (e *ExpectedPing) AtMost(n int) *ExpectedPing {
+	e.atMost(n)
+	return e
+}
+
+// AnyTimes allows the database Ping to be called any number
+// of times, including none at all.
+func (e *ExpectedPing) AnyTimes() *ExpectedPing {
+	e.anyTimes()
+	return e
+}
+
+// Maybe marks the database Ping as optional, it may be matched
+// but ExpectationsWereMet does not require it to be.
+func (e *ExpectedPing) Maybe() *ExpectedPing {
+	e.optional = true
+	return e
+}
+
+// OnConn expects the database Ping to be called on the n-th
+// connection opened for the mock database, counted from zero.
+func (e *ExpectedPing) OnConn(n int) *ExpectedPing {
+	e.pinned, e.connIndex = true, n
+	return e
+}
+
 // String returns string representation
 func (e *ExpectedPing) String() string {
 	msg := "ExpectedPing => expecting database Ping"
@@ -307,6 +1129,13 @@ func (e *ExpectedOperation) WillReturnError(err error) *ExpectedOperation {
 	return e
 }
 
+// Maybe marks the operation as optional, it may be matched
+// but ExpectationsWereMet does not require it to be.
+func (e *ExpectedOperation) Maybe() *ExpectedOperation {
+	e.optional = true
+	return e
+}
+
 // String returns string representation
 func (e *ExpectedOperation) String() string {
 	msg := "ExpectedOperation => expecting database transaction Begin"
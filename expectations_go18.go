@@ -4,14 +4,21 @@
 package sqlmock
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"fmt"
 	"log"
 	"reflect"
+	"sort"
+	"strings"
 )
 
 func (e *queryBasedExpectation) argsMatches(args []driver.NamedValue) error {
+	if e.namedArgs != nil {
+		return e.mixedArgsMatches(args)
+	}
+
 	if len(args) != len(e.args) {
 		log.Printf("arguments not match\n expected => %s\n actual   => %s \n", jsonify(e.args), jsonify(convValue(args)))
 		return fmt.Errorf("expected %d, but got %d arguments", len(e.args), len(args))
@@ -23,7 +30,7 @@ func (e *queryBasedExpectation) argsMatches(args []driver.NamedValue) error {
 		matcher, ok := e.args[k].(Matcher)
 		if ok {
 			if !matcher.Match(v.Value) {
-				return fmt.Errorf("matcher %T could not match %d argument %T - %+v", matcher, k, args[k], args[k])
+				return fmt.Errorf("matcher %s could not match %d argument %T - %+v", describeMatcher(matcher), k, args[k], args[k])
 			}
 			continue
 		}
@@ -38,19 +45,129 @@ func (e *queryBasedExpectation) argsMatches(args []driver.NamedValue) error {
 			return fmt.Errorf("argument %d: ordinal position: %d does not match expected: %d", k, k+1, v.Ordinal)
 		}
 
-		// convert to driver converter
-		darg, err := e.converter.ConvertValue(dval)
-		if err != nil {
-			return fmt.Errorf("could not convert %d argument %T - %+v to driver value: %s", k, e.args[k], e.args[k], err)
+		if err := e.valueMatches(fmt.Sprintf("argument %d", k), dval, v.Value); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		if !reflect.DeepEqual(darg, v.Value) {
-			return fmt.Errorf("argument %d expected [%T - %+v] does not match actual [%T - %+v]", k, darg, darg, v.Value, v.Value)
+// valueMatches compares an expected argument value, once converted
+// by the driver converter, with the actual argument value
+func (e *queryBasedExpectation) valueMatches(arg string, expected driver.Value, actual driver.Value) error {
+	// out parameters are passed as is by CheckNamedValue
+	if out, isOut := expected.(sql.Out); isOut {
+		if !reflect.DeepEqual(out, actual) {
+			return fmt.Errorf("%s expected [%T - %+v] does not match actual [%T - %+v]", arg, out, out, actual, actual)
 		}
+		return nil
+	}
+
+	// convert to driver converter
+	darg, err := e.converter.ConvertValue(expected)
+	if err != nil {
+		return fmt.Errorf("could not convert %s %T - %+v to driver value: %s", arg, expected, expected, err)
+	}
+
+	if !reflect.DeepEqual(darg, actual) {
+		return fmt.Errorf("%s expected [%T - %+v] does not match actual [%T - %+v]", arg, darg, darg, actual, actual)
 	}
 	return nil
 }
 
+// mixedArgsMatches checks the positional arguments against WithArgs
+// in their relative order and the named arguments against WithNamedArgs
+// by name, regardless of their position
+func (e *queryBasedExpectation) mixedArgsMatches(args []driver.NamedValue) error {
+	var positional []driver.NamedValue
+	named := make(map[string]driver.NamedValue)
+	for _, arg := range args {
+		if arg.Name == "" {
+			positional = append(positional, arg)
+			continue
+		}
+		named[arg.Name] = arg
+	}
+
+	if len(positional) != len(e.args) {
+		return fmt.Errorf("expected %d, but got %d positional arguments", len(e.args), len(positional))
+	}
+	for k, v := range positional {
+		if matcher, ok := e.args[k].(Matcher); ok {
+			if !matcher.Match(v.Value) {
+				return fmt.Errorf("matcher %s could not match positional argument %d %T - %+v", describeMatcher(matcher), k, v.Value, v.Value)
+			}
+			continue
+		}
+		if err := e.valueMatches(fmt.Sprintf("positional argument %d", k), e.args[k], v.Value); err != nil {
+			return err
+		}
+	}
+
+	var diff []string
+	for _, name := range sortedNames(e.namedArgs) {
+		expected := e.namedArgs[name]
+		actual, ok := named[name]
+		if !ok {
+			diff = append(diff, fmt.Sprintf("missing :%s, expected [%T - %+v]", name, expected, expected))
+			continue
+		}
+		if matcher, ok := expected.(Matcher); ok {
+			if !matcher.Match(actual.Value) {
+				diff = append(diff, fmt.Sprintf("matcher %s could not match :%s [%T - %+v]", describeMatcher(matcher), name, actual.Value, actual.Value))
+			}
+			continue
+		}
+		if err := e.valueMatches(":"+name, expected, actual.Value); err != nil {
+			diff = append(diff, err.Error())
+		}
+	}
+	for _, name := range sortedNames(named) {
+		if _, ok := e.namedArgs[name]; !ok {
+			diff = append(diff, fmt.Sprintf("unexpected :%s [%T - %+v]", name, named[name].Value, named[name].Value))
+		}
+	}
+
+	if len(diff) > 0 {
+		return fmt.Errorf("named arguments do not match:\n  - %s", strings.Join(diff, "\n  - "))
+	}
+	return nil
+}
+
+// captureArgs stores the arguments of a matched call
+// into the destinations of Capture matchers
+func (e *queryBasedExpectation) captureArgs(args []driver.NamedValue) {
+	var positional int
+	for i, arg := range args {
+		expected := driver.Value(nil)
+		switch {
+		case e.namedArgs != nil && arg.Name != "":
+			expected = e.namedArgs[arg.Name]
+		case e.namedArgs != nil:
+			if positional < len(e.args) {
+				expected = e.args[positional]
+			}
+			positional++
+		case i < len(e.args):
+			expected = e.args[i]
+		}
+		if c, ok := expected.(capturer); ok {
+			c.capture(arg.Value)
+		}
+	}
+}
+
+// sortedNames returns the keys of a map keyed by argument name in order
+func sortedNames(m interface{}) []string {
+	keys := reflect.ValueOf(m).MapKeys()
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = k.String()
+	}
+	sort.Strings(names)
+	return names
+}
+
 func (e *queryBasedExpectation) attemptArgMatch(args []driver.NamedValue) (err error) {
 	// catch panic
 	defer func() {
@@ -65,3 +182,101 @@ func (e *queryBasedExpectation) attemptArgMatch(args []driver.NamedValue) (err e
 	err = e.argsMatches(args)
 	return
 }
+
+// WillRespond allows to compute the rows or result of the expected sql
+// from the actual call, for example to echo inserted ids, to filter
+// rows by the bound arguments or to fail only for specific inputs.
+// The callback runs after the expectation is matched and after any
+// WillDelayFor delay, it receives the context of the call.
+func (e *ExpectedSql) WillRespond(respond func(ctx context.Context, query string, args []driver.NamedValue) (*Rows, driver.Result, error)) *ExpectedSql {
+	e.respond = respond
+	return e
+}
+
+// WillRespond computes the rows of the query from the actual call,
+// an error returned by respond is returned by the query.
+func (e *ExpectedQuery) WillRespond(respond func(ctx context.Context, query string, args []driver.NamedValue) (*Rows, error)) *ExpectedQuery {
+	e.ex.WillRespond(func(ctx context.Context, query string, args []driver.NamedValue) (*Rows, driver.Result, error) {
+		rows, err := respond(ctx, query, args)
+		return rows, nil, err
+	})
+	return e
+}
+
+// WillRespond computes the result of the exec from the actual call,
+// an error returned by respond is returned by the exec.
+func (e *ExpectedExec) WillRespond(respond func(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error)) *ExpectedExec {
+	e.ex.WillRespond(func(ctx context.Context, query string, args []driver.NamedValue) (*Rows, driver.Result, error) {
+		result, err := respond(ctx, query, args)
+		return nil, result, err
+	})
+	return e
+}
+
+// checkStmt verifies that an expectation bound to a prepared statement
+// is triggered through a statement created by that prepare expectation
+func (e *ExpectedSql) checkStmt(stmt *statement, call string) error {
+	if e.prepared == nil {
+		return nil
+	}
+	if stmt == nil {
+		return newError(ErrWrongStatement, "%s was expected on the statement prepared for '%s', but it was not called on a prepared statement", call, e.prepared.expectSQL)
+	}
+	if stmt.ex != e.prepared {
+		return newError(ErrWrongStatement, "%s was expected on the statement prepared for '%s', but it was called on another prepared statement", call, e.prepared.expectSQL)
+	}
+	return nil
+}
+
+// queryRows returns the rows of a matched query, computed by
+// the WillRespond callback when one is set
+func (e *ExpectedSql) queryRows(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := e.setOutParams(args); err != nil {
+		return nil, err
+	}
+
+	if e.respond == nil {
+		rows := e.rows.rewind()
+		rows.ctx = ctx
+		return e.track(rows), nil
+	}
+
+	rows, _, err := e.respond(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	if rows == nil {
+		return nil, newError(ErrNoResult, "query '%s' with args %+v, WillRespond must return a *Rows, but it returned nil", query, args)
+	}
+	return e.track(&rowSets{sets: []*Rows{rows}, ex: e, ctx: ctx}), nil
+}
+
+// track keeps the rows returned by every call, so that
+// each of them left open can be reported
+func (e *ExpectedSql) track(rows *rowSets) *rowSets {
+	e.Lock()
+	defer e.Unlock()
+	e.returnedRows = append(e.returnedRows, rows)
+	return rows
+}
+
+// execResult returns the result of a matched exec, computed by
+// the WillRespond callback when one is set
+func (e *ExpectedSql) execResult(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := e.setOutParams(args); err != nil {
+		return nil, err
+	}
+
+	if e.respond == nil {
+		return e.result, nil
+	}
+
+	_, result, err := e.respond(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, newError(ErrNoResult, "ExecQuery '%s' with args %+v, WillRespond must return a database/sql/driver.Result, but it returned nil", query, args)
+	}
+	return result, nil
+}
@@ -173,3 +173,76 @@ func Test_queryBasedExpectation_attemptArgMatch(t *testing.T) {
 		t.Errorf("error expected")
 	}
 }
+
+func TestQueryExpectationNamedArgsByName(t *testing.T) {
+	e := &queryBasedExpectation{converter: driver.DefaultParameterConverter}
+	e.namedArgs = map[string]driver.Value{"id": 5, "name": Any()}
+
+	against := []driver.NamedValue{
+		{Name: "name", Value: "john", Ordinal: 1},
+		{Name: "id", Value: int64(5), Ordinal: 2},
+	}
+	if err := e.argsMatches(against); err != nil {
+		t.Errorf("named arguments should match regardless of their position, but got: %s", err)
+	}
+
+	e.args = []driver.Value{"active"}
+	against = []driver.NamedValue{
+		{Name: "id", Value: int64(5), Ordinal: 1},
+		{Value: "active", Ordinal: 2},
+		{Name: "name", Value: "john", Ordinal: 3},
+	}
+	if err := e.argsMatches(against); err != nil {
+		t.Errorf("positional and named arguments should match separately, but got: %s", err)
+	}
+
+	against[1].Value = "deleted"
+	if err := e.argsMatches(against); err == nil {
+		t.Error("positional argument should not match")
+	}
+}
+
+func TestQueryExpectationNamedArgsDiff(t *testing.T) {
+	e := &queryBasedExpectation{converter: driver.DefaultParameterConverter}
+	e.namedArgs = map[string]driver.Value{"id": 5, "name": "john", "age": 30}
+
+	against := []driver.NamedValue{
+		{Name: "id", Value: int64(6), Ordinal: 1},
+		{Name: "age", Value: int64(30), Ordinal: 2},
+		{Name: "email", Value: "john@example.com", Ordinal: 3},
+	}
+
+	err := e.argsMatches(against)
+	if err == nil {
+		t.Fatal("named arguments should not match")
+	}
+
+	want := "named arguments do not match:\n" +
+		"  - :id expected [int64 - 5] does not match actual [int64 - 6]\n" +
+		"  - missing :name, expected [string - john]\n" +
+		"  - unexpected :email [string - john@example.com]"
+	if err.Error() != want {
+		t.Errorf("expected error:\n%s\nbut got:\n%s", want, err)
+	}
+}
+
+func TestQueryWithNamedArgs(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE users").
+		WithNamedArgs(map[string]driver.Value{"id": 1, "name": "john"}).
+		WillReturnResult(NewResult(0, 1))
+
+	_, err = db.Exec("UPDATE users SET name = :name WHERE id = :id", sql.Named("id", 1), sql.Named("name", "john"))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected while executing", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
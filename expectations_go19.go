@@ -0,0 +1,229 @@
+//go:build go1.9
+// +build go1.9
+
+package sqlmock
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// WillReturnOutParams allows to set the values written to the sql.Out
+// destinations of named arguments, such as the output parameters of a
+// stored procedure, keyed by argument name.
+func (e *ExpectedSql) WillReturnOutParams(params map[string]interface{}) *ExpectedSql {
+	if e.outParams == nil {
+		e.outParams = make(map[string]interface{})
+	}
+	for name, v := range params {
+		e.outParams[name] = v
+	}
+	return e
+}
+
+// WillReturnOutParamsByOrdinal allows to set the values written to the
+// sql.Out destinations of arguments, keyed by their ordinal position
+// starting from 1.
+func (e *ExpectedSql) WillReturnOutParamsByOrdinal(params map[int]interface{}) *ExpectedSql {
+	if e.outOrdinals == nil {
+		e.outOrdinals = make(map[int]interface{})
+	}
+	for ordinal, v := range params {
+		e.outOrdinals[ordinal] = v
+	}
+	return e
+}
+
+// WillReturnOutParams allows to set the values written to the sql.Out
+// destinations of named arguments, keyed by argument name.
+func (e *ExpectedQuery) WillReturnOutParams(params map[string]interface{}) *ExpectedQuery {
+	e.ex.WillReturnOutParams(params)
+	return e
+}
+
+// WillReturnOutParamsByOrdinal allows to set the values written to the
+// sql.Out destinations of arguments, keyed by their ordinal position.
+func (e *ExpectedQuery) WillReturnOutParamsByOrdinal(params map[int]interface{}) *ExpectedQuery {
+	e.ex.WillReturnOutParamsByOrdinal(params)
+	return e
+}
+
+// WillReturnOutParams allows to set the values written to the sql.Out
+// destinations of named arguments, keyed by argument name.
+func (e *ExpectedExec) WillReturnOutParams(params map[string]interface{}) *ExpectedExec {
+	e.ex.WillReturnOutParams(params)
+	return e
+}
+
+// WillReturnOutParamsByOrdinal allows to set the values written to the
+// sql.Out destinations of arguments, keyed by their ordinal position.
+func (e *ExpectedExec) WillReturnOutParamsByOrdinal(params map[int]interface{}) *ExpectedExec {
+	e.ex.WillReturnOutParamsByOrdinal(params)
+	return e
+}
+
+// describeOutParams lists the out parameter values for String
+func (e *ExpectedSql) describeOutParams() string {
+	var params []string
+	for name, v := range e.outParams {
+		params = append(params, fmt.Sprintf("%s: %+v", name, v))
+	}
+	for ordinal, v := range e.outOrdinals {
+		params = append(params, fmt.Sprintf("%d: %+v", ordinal, v))
+	}
+	sort.Strings(params)
+	return strings.Join(params, ", ")
+}
+
+// setOutParams writes the expected out parameter values
+// to the sql.Out destinations of the call arguments
+func (e *ExpectedSql) setOutParams(args []driver.NamedValue) error {
+	if len(e.outParams) == 0 && len(e.outOrdinals) == 0 {
+		return nil
+	}
+
+	names := make(map[string]bool, len(e.outParams))
+	ordinals := make(map[int]bool, len(e.outOrdinals))
+	for _, arg := range args {
+		param := fmt.Sprintf("%d", arg.Ordinal)
+		v, ok := e.outOrdinals[arg.Ordinal]
+		if ok {
+			ordinals[arg.Ordinal] = true
+		}
+		if arg.Name != "" {
+			param = arg.Name
+			if nv, found := e.outParams[arg.Name]; found {
+				v, ok = nv, true
+				names[arg.Name] = true
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		out, isOut := arg.Value.(sql.Out)
+		if !isOut {
+			return newError(ErrOutParam, "out parameter %s: argument %T - %+v is not an sql.Out", param, arg.Value, arg.Value)
+		}
+		if err := e.setOut(param, out, v); err != nil {
+			return err
+		}
+	}
+
+	for name := range e.outParams {
+		if !names[name] {
+			return newError(ErrOutParam, "out parameter %s was expected, but there is no such named sql.Out argument", name)
+		}
+	}
+	for ordinal := range e.outOrdinals {
+		if !ordinals[ordinal] {
+			return newError(ErrOutParam, "out parameter %d was expected, but there is no sql.Out argument at this position", ordinal)
+		}
+	}
+	return nil
+}
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// setOut assigns the value to the destination of an sql.Out parameter,
+// the input of an inout parameter must be a valid driver value
+func (e *ExpectedSql) setOut(param string, out sql.Out, v interface{}) error {
+	dest := reflect.ValueOf(out.Dest)
+	if dest.Kind() != reflect.Ptr || dest.IsNil() {
+		return newError(ErrOutParam, "out parameter %s: destination must be a non nil pointer, but got %T", param, out.Dest)
+	}
+
+	if out.In {
+		if _, err := e.converter.ConvertValue(dest.Elem().Interface()); err != nil {
+			return newError(ErrOutParam, "inout parameter %s: input %T - %+v is not a valid driver value: %s", param, dest.Elem().Interface(), dest.Elem().Interface(), err)
+		}
+	}
+
+	if dest.Type().Implements(scannerType) {
+		if err := out.Dest.(sql.Scanner).Scan(v); err != nil {
+			return newError(ErrOutParam, "out parameter %s: %s", param, err)
+		}
+		return nil
+	}
+
+	elem := dest.Elem()
+	if v == nil {
+		switch elem.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+			elem.Set(reflect.Zero(elem.Type()))
+			return nil
+		}
+		return newError(ErrOutParam, "out parameter %s: cannot assign NULL to destination %T", param, out.Dest)
+	}
+
+	val := reflect.ValueOf(v)
+	switch {
+	case val.Type().AssignableTo(elem.Type()):
+		elem.Set(val)
+	case isNumber(val.Kind()) && isNumber(elem.Kind()):
+		if !fitsNumber(val, elem) {
+			return newError(ErrOutParam, "out parameter %s: %T - %+v does not fit in destination %T", param, v, v, out.Dest)
+		}
+		elem.Set(val.Convert(elem.Type()))
+	case val.Kind() == reflect.String && elem.Kind() == reflect.Slice && elem.Type().Elem().Kind() == reflect.Uint8,
+		val.Kind() == reflect.Slice && val.Type().Elem().Kind() == reflect.Uint8 && elem.Kind() == reflect.String:
+		elem.Set(val.Convert(elem.Type()))
+	default:
+		return newError(ErrOutParam, "out parameter %s: cannot assign %T - %+v to destination %T", param, v, v, out.Dest)
+	}
+	return nil
+}
+
+// fitsNumber tells whether the number val converts to the kind of dest
+// without truncating a fraction, wrapping a sign or overflowing
+func fitsNumber(val, dest reflect.Value) bool {
+	switch dest.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch val.Kind() {
+		case reflect.Float32, reflect.Float64:
+			f := val.Float()
+			if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+				return false
+			}
+			return !dest.OverflowInt(int64(f))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return val.Uint() <= math.MaxInt64 && !dest.OverflowInt(int64(val.Uint()))
+		}
+		return !dest.OverflowInt(val.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch val.Kind() {
+		case reflect.Float32, reflect.Float64:
+			f := val.Float()
+			if f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 {
+				return false
+			}
+			return !dest.OverflowUint(uint64(f))
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return val.Int() >= 0 && !dest.OverflowUint(uint64(val.Int()))
+		}
+		return !dest.OverflowUint(val.Uint())
+	}
+
+	switch val.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return !dest.OverflowFloat(val.Float())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return !dest.OverflowFloat(float64(val.Uint()))
+	}
+	return !dest.OverflowFloat(float64(val.Int()))
+}
+
+func isNumber(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
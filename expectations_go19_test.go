@@ -5,7 +5,11 @@ package sqlmock
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestCustomValueConverterExec(t *testing.T) {
@@ -42,3 +46,124 @@ func TestCustomValueConverterExec(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestExecOutParams(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	var total int64
+	var status string
+	var note sql.NullString
+	counter := 5
+
+	mock.ExpectExec("CALL order_totals").
+		WithArgs(1, sql.Named("total", sql.Out{Dest: &total}), sql.Out{Dest: &status}, sql.Named("counter", sql.Out{Dest: &counter, In: true}), sql.Named("note", sql.Out{Dest: &note})).
+		WillReturnOutParams(map[string]interface{}{"total": 42, "counter": 6, "note": nil}).
+		WillReturnOutParamsByOrdinal(map[int]interface{}{3: []byte("paid")}).
+		WillReturnResult(NewResult(0, 0))
+
+	_, err = db.Exec("CALL order_totals(?, ?, ?, ?, ?)",
+		1,
+		sql.Named("total", sql.Out{Dest: &total}),
+		sql.Out{Dest: &status},
+		sql.Named("counter", sql.Out{Dest: &counter, In: true}),
+		sql.Named("note", sql.Out{Dest: &note}),
+	)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected while executing", err)
+	}
+
+	if total != 42 || status != "paid" || counter != 6 || note.Valid {
+		t.Errorf("unexpected out params: total %d, status %q, counter %d, note %+v", total, status, counter, note)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestExecOutParamsNumberConversions(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	var small int8
+	var count int
+	var unsigned uint16
+	var ratio float32
+
+	mock.ExpectExec("CALL stats").
+		WithArgs(Any(), Any(), Any(), Any()).
+		WillReturnOutParams(map[string]interface{}{"small": int64(-128), "count": 2.0, "unsigned": 65535, "ratio": 0.5}).
+		WillReturnResult(NewResult(0, 0))
+
+	_, err = db.Exec("CALL stats(?, ?, ?, ?)",
+		sql.Named("small", sql.Out{Dest: &small}),
+		sql.Named("count", sql.Out{Dest: &count}),
+		sql.Named("unsigned", sql.Out{Dest: &unsigned}),
+		sql.Named("ratio", sql.Out{Dest: &ratio}),
+	)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected while executing", err)
+	}
+
+	if small != -128 || count != 2 || unsigned != 65535 || ratio != 0.5 {
+		t.Errorf("unexpected out params: small %d, count %d, unsigned %d, ratio %v", small, count, unsigned, ratio)
+	}
+}
+
+func TestExecOutParamsErrors(t *testing.T) {
+	var total time.Time
+	var in chan int
+	var small int8
+	var count int
+	var unsigned uint
+	var ratio float32
+	cases := []struct {
+		name   string
+		arg    interface{}
+		params map[string]interface{}
+		err    string
+	}{
+		{"type", sql.Named("total", sql.Out{Dest: &total}), map[string]interface{}{"total": 42}, "out parameter total: cannot assign int - 42 to destination *time.Time"},
+		{"missing", sql.Named("sum", sql.Out{Dest: &total}), map[string]interface{}{"total": 42}, "out parameter total was expected, but there is no such named sql.Out argument"},
+		{"not out", sql.Named("total", 1), map[string]interface{}{"total": 42}, "out parameter total: argument int64 - 1 is not an sql.Out"},
+		{"inout", sql.Named("total", sql.Out{Dest: &in, In: true}), map[string]interface{}{"total": nil}, "inout parameter total: input chan int"},
+		{"overflow", sql.Named("total", sql.Out{Dest: &small}), map[string]interface{}{"total": int64(300)}, "out parameter total: int64 - 300 does not fit in destination *int8"},
+		{"fraction", sql.Named("total", sql.Out{Dest: &count}), map[string]interface{}{"total": 1.9}, "out parameter total: float64 - 1.9 does not fit in destination *int"},
+		{"negative", sql.Named("total", sql.Out{Dest: &unsigned}), map[string]interface{}{"total": -1}, "out parameter total: int - -1 does not fit in destination *uint"},
+		{"float overflow", sql.Named("total", sql.Out{Dest: &ratio}), map[string]interface{}{"total": 1e300}, "out parameter total: float64 - 1e+300 does not fit in destination *float32"},
+		{"uint overflow", sql.Named("total", sql.Out{Dest: &count}), map[string]interface{}{"total": uint64(1 << 63)}, "out parameter total: uint64 - 9223372036854775808 does not fit in destination *int"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db, mock, err := New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mock.ExpectExec("CALL order_totals").
+				WithArgs(Any()).
+				WillReturnOutParams(c.params).
+				WillReturnResult(NewResult(0, 0))
+
+			_, err = db.Exec("CALL order_totals(?)", c.arg)
+			if err == nil || !strings.HasPrefix(err.Error(), c.err) {
+				t.Errorf("expected error starting with %q, but got: %v", c.err, err)
+			}
+			if !errors.Is(err, ErrOutParam) {
+				t.Errorf("expected an out parameter error, but got: %v", err)
+			}
+			if calls := mock.UnexpectedCalls(); len(calls) != 1 || calls[0].Method != "Exec" {
+				t.Errorf("expected the failed exec to be journaled, but got: %v", calls)
+			}
+		})
+	}
+}
@@ -2,8 +2,10 @@ package sqlmock
 
 import (
 	"database/sql/driver"
+	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -25,7 +27,7 @@ func (s CustomConverter) ConvertValue(v interface{}) (driver.Value, error) {
 func ExampleExpectedExec() {
 	db, mock, _ := New()
 	result := NewErrorResult(fmt.Errorf("some error"))
-	mock.ExpectSql(nil, "^INSERT (.+)").WillReturnResult(result)
+	mock.ExpectExec("^INSERT (.+)").WillReturnResult(result)
 	res, _ := db.Exec("INSERT something")
 	_, err := res.LastInsertId()
 	fmt.Println(err)
@@ -48,7 +50,7 @@ func TestBuildQuery(t *testing.T) {
 
 	`
 
-	mock.ExpectSql(nil, query)
+	mock.ExpectSql(nil, query).Times(2)
 	mock.ExpectPrepare(query)
 
 	db.QueryRow(query)
@@ -99,3 +101,248 @@ func TestCustomValueConverterQueryScan(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestExpectedSqlTimes(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rs := NewRows([]string{"id"}).AddRow(1)
+	mock.ExpectSql(Query(), "SELECT id FROM users").WillReturnRows(rs).Times(3)
+
+	for i := 0; i < 3; i++ {
+		var id int
+		if err := db.QueryRow("SELECT id FROM users").Scan(&id); err != nil {
+			t.Fatalf("call %d: unexpected error: %s", i, err)
+		}
+		if id != 1 {
+			t.Errorf("call %d: expected id to be 1, but got %d", i, id)
+		}
+	}
+
+	if _, err := db.Query("SELECT id FROM users"); err == nil {
+		t.Error("expected an error on the fourth call, but got none")
+	}
+
+	if err := mock.ExpectationsWereMet(); !errors.Is(err, ErrUnexpectedCall) || errors.Is(err, ErrUnmetExpectations) {
+		t.Errorf("expected only the fourth call to be reported, but got: %v", err)
+	}
+}
+
+func TestExpectedSqlTimesNotMet(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectSql(Exec(), "DELETE FROM users").WillReturnResult(NewResult(0, 1)).Times(3)
+
+	for i := 0; i < 2; i++ {
+		if _, err := db.Exec("DELETE FROM users"); err != nil {
+			t.Fatalf("call %d: unexpected error: %s", i, err)
+		}
+	}
+
+	err = mock.ExpectationsWereMet()
+	if err == nil {
+		t.Fatal("expected an error since the exec was called only twice")
+	}
+	if !strings.Contains(err.Error(), "called 2 times, expected 3 times") {
+		t.Errorf("expected error to report call counts, but got: %s", err)
+	}
+}
+
+func TestExpectedSqlTimesBounds(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectSql(Exec(), "UPDATE users").WillReturnResult(NewResult(0, 1)).AtMost(1).AtLeast(1)
+	mock.ExpectSql(Exec(), "DELETE FROM users").WillReturnResult(NewResult(0, 1)).AtMost(2)
+
+	if _, err := db.Exec("UPDATE users"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := db.Exec("UPDATE users"); err == nil {
+		t.Error("expected an error on the second call, since the update is expected exactly once")
+	}
+
+	// the delete is never called, which is allowed by AtMost
+	err = mock.ExpectationsWereMet()
+	if !errors.Is(err, ErrUnexpectedCall) || errors.Is(err, ErrUnmetExpectations) {
+		t.Errorf("expected only the second update to be reported, but got: %v", err)
+	}
+}
+
+func TestRepeatedExpectationsInOrder(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin().AtLeast(1)
+	mock.ExpectSql(Exec(), "UPDATE users").WillReturnResult(NewResult(0, 1)).AnyTimes()
+	mock.ExpectCommit().AtMost(2)
+	mock.ExpectSql(Exec(), "DELETE FROM users").WillReturnResult(NewResult(0, 1))
+
+	for i := 0; i < 2; i++ {
+		tx, err := db.Begin()
+		if err != nil {
+			t.Fatalf("call %d: unexpected error on begin: %s", i, err)
+		}
+		if _, err := tx.Exec("UPDATE users"); err != nil {
+			t.Fatalf("call %d: unexpected error on update: %s", i, err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("call %d: unexpected error on commit: %s", i, err)
+		}
+	}
+
+	if _, err := db.Exec("DELETE FROM users"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestOptionalExpectations(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectSql(Query(), "SELECT id FROM cache").
+		WillReturnRows(NewRows([]string{"id"})).
+		Maybe()
+	mock.ExpectBegin().Maybe()
+	mock.ExpectSql(Exec(), "UPDATE users").WillReturnResult(NewResult(0, 1))
+	mock.ExpectSql(Query(), "SELECT id FROM users").
+		WillReturnRows(NewRows([]string{"id"}).AddRow(1)).
+		Maybe()
+	mock.ExpectCommit().Maybe()
+
+	// the optional cache query and begin are skipped in order
+	if _, err := db.Exec("UPDATE users"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// an optional expectation may still be consumed
+	var id int
+	if err := db.QueryRow("SELECT id FROM users").Scan(&id); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestOptionalExpectationDoesNotHideRequired(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectPrepare("SELECT id FROM cache").Maybe()
+	mock.ExpectSql(Exec(), "UPDATE users").WillReturnResult(NewResult(0, 1))
+
+	if err := mock.ExpectationsWereMet(); err == nil {
+		t.Error("expected an error since the required exec was not called")
+	}
+}
+
+func TestExpectQueryAndExec(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT id FROM users").
+		WithArgs(1).
+		WillReturnRows(NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec("UPDATE users").
+		WithArgs(1).
+		WillReturnResult(NewResult(0, 1))
+
+	var id int
+	if err := db.QueryRow("SELECT id FROM users WHERE id = ?", 1).Scan(&id); err != nil {
+		t.Fatalf("an error '%s' was not expected while querying", err)
+	}
+	if _, err := db.Exec("UPDATE users SET name = 'john' WHERE id = ?", 1); err != nil {
+		t.Fatalf("an error '%s' was not expected while executing", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestExpectExecCalledAsQuery(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("DELETE FROM users").WillReturnResult(NewResult(0, 1))
+
+	_, err = db.Query("DELETE FROM users")
+	if err == nil {
+		t.Fatal("expected an error for a query matching an exec expectation")
+	}
+	if !strings.Contains(err.Error(), "next expectation is for another operation") ||
+		!strings.Contains(err.Error(), "ExpectedExec => expecting Exec or ExecContext") {
+		t.Errorf("expected an operation mismatch diagnostic, but got: %s", err)
+	}
+
+	if _, err := db.Exec("DELETE FROM users"); err != nil {
+		t.Errorf("an error '%s' was not expected while executing", err)
+	}
+}
+
+func TestExpectQueryCalledAsExecUnordered(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.MatchExpectationsInOrder(false)
+	mock.ExpectQuery("SELECT id FROM users").WillReturnRows(NewRows([]string{"id"}).AddRow(1))
+
+	_, err = db.Exec("SELECT id FROM users")
+	if err == nil {
+		t.Fatal("expected an error for an exec matching a query expectation")
+	}
+	if !strings.Contains(err.Error(), "call to ExecQuery 'SELECT id FROM users' with args [] was not expected") ||
+		!strings.Contains(err.Error(), "reason: operation exec is not expected") {
+		t.Errorf("expected an operation mismatch diagnostic, but got: %s", err)
+	}
+
+	// the mismatched expectation must have been unlocked
+	var id int
+	if err := db.QueryRow("SELECT id FROM users").Scan(&id); err != nil {
+		t.Fatalf("an error '%s' was not expected while querying", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); !errors.Is(err, ErrUnexpectedCall) || errors.Is(err, ErrUnmetExpectations) {
+		t.Errorf("expected only the exec to be reported, but got: %v", err)
+	}
+}
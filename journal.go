@@ -0,0 +1,60 @@
+package sqlmock
+
+import (
+	"errors"
+	"time"
+)
+
+// UnexpectedCall is a call to the driver which did not match the
+// expectations, or whose out parameters could not be assigned. It is
+// recorded in the journal of the mock, whether or not the code under
+// test handles the error it was returned.
+type UnexpectedCall struct {
+	// Method is the called driver method, such as "Begin" or "Query".
+	Method string
+
+	// Err is the error returned for the call.
+	Err error
+
+	// Time is when the call happened.
+	Time time.Time
+}
+
+func (u UnexpectedCall) String() string {
+	return "unexpected call to " + u.Method + ": " + u.Err.Error()
+}
+
+// unexpectedKinds are the failures of a call recorded in the journal
+var unexpectedKinds = []error{ErrUnexpectedCall, ErrWrongConnection, ErrWrongTx, ErrWrongStatement, ErrStmtClosed, ErrOutParam}
+
+// record journals the error of a call when it is unexpected and
+// reports it at once in strict mode, the error is returned as is
+func (c *sqlmock) record(method string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	for _, kind := range unexpectedKinds {
+		if !errors.Is(err, kind) {
+			continue
+		}
+
+		call := UnexpectedCall{Method: method, Err: err, Time: time.Now()}
+		c.journalLock.Lock()
+		c.journal = append(c.journal, call)
+		strict := c.strict
+		c.journalLock.Unlock()
+
+		if strict != nil {
+			strict(call)
+		}
+		break
+	}
+	return err
+}
+
+func (c *sqlmock) UnexpectedCalls() []UnexpectedCall {
+	c.journalLock.Lock()
+	defer c.journalLock.Unlock()
+	return append([]UnexpectedCall(nil), c.journal...)
+}
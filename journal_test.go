@@ -0,0 +1,127 @@
+package sqlmock
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type recordingT struct {
+	errors []string
+}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestUnexpectedCallsAreJournaled(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE users").WillReturnResult(NewResult(0, 1))
+
+	// errors swallowed by the code under test
+	_, _ = db.Begin()
+	_, _ = db.Exec("UPDATE users")
+	_, _ = db.Query("SELECT name FROM users")
+
+	calls := mock.UnexpectedCalls()
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 unexpected calls, but got %d: %v", len(calls), calls)
+	}
+	if calls[0].Method != "Begin" || calls[1].Method != "Query" {
+		t.Errorf("expected Begin and Query to be journaled, but got: %s and %s", calls[0].Method, calls[1].Method)
+	}
+	if !errors.Is(calls[1].Err, ErrUnexpectedCall) || calls[1].Time.IsZero() {
+		t.Errorf("unexpected journal entry: %+v", calls[1])
+	}
+
+	err = mock.ExpectationsWereMet()
+	if !errors.Is(err, ErrUnexpectedCall) || errors.Is(err, ErrUnmetExpectations) {
+		t.Fatalf("expected the unexpected calls to be reported, but got: %v", err)
+	}
+	if !strings.HasPrefix(err.Error(), "there are 2 problems with the expectations:\n  1) unexpected call to Begin: ") {
+		t.Errorf("unexpected report: %s", err)
+	}
+	var mismatch *MismatchError
+	if !errors.As(err, &mismatch) || mismatch.Query != "SELECT name FROM users" {
+		t.Errorf("expected to find the *MismatchError of the query in: %v", err)
+	}
+}
+
+func TestLegacyCallsAreJournaled(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	c := &conn{sqlmock: mock.(*sqlmock)}
+	_, _ = c.Query("SELECT name FROM users", nil)
+	_, _ = c.Exec("DELETE FROM users", nil)
+
+	calls := mock.UnexpectedCalls()
+	if len(calls) != 2 || calls[0].Method != "Query" || calls[1].Method != "Exec" {
+		t.Errorf("expected Query and Exec to be journaled, but got: %v", calls)
+	}
+}
+
+func TestCloseIsJournaledWhenExpected(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectClose()
+
+	if err := db.Close(); err == nil {
+		t.Fatal("expected an error for the close before the begin")
+	}
+
+	calls := mock.UnexpectedCalls()
+	if len(calls) != 1 || calls[0].Method != "Close" {
+		t.Errorf("expected the close to be journaled, but got: %v", calls)
+	}
+}
+
+func TestStrictOption(t *testing.T) {
+	rt := &recordingT{}
+	db, mock, err := New(StrictOption(rt))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectCommit()
+	_, _ = db.Exec("DELETE FROM users")
+
+	if len(rt.errors) != 1 {
+		t.Fatalf("expected the unexpected call to be reported at once, but got: %q", rt.errors)
+	}
+	if !strings.HasPrefix(rt.errors[0], "sqlmock: unexpected call to Exec: call to ExecQuery 'DELETE FROM users' with args [], was not expected") {
+		t.Errorf("unexpected report: %s", rt.errors[0])
+	}
+}
+
+func TestStrictOptionPanics(t *testing.T) {
+	db, mock, err := New(StrictOption(nil))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	defer func() {
+		msg, ok := recover().(string)
+		if !ok || !strings.HasPrefix(msg, "sqlmock: unexpected call to Exec: ") {
+			t.Errorf("expected a panic for the unexpected call, but got: %v", msg)
+		}
+	}()
+	_, _ = db.Exec("DELETE FROM users")
+	t.Error("expected a panic for the unexpected call")
+}
@@ -1,6 +1,14 @@
 package sqlmock
 
-import "database/sql/driver"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
 
 // Matcher interface allows to match
 // any argument in specific way when used with Expected expectations.
@@ -27,3 +35,341 @@ func Exec() Matcher {
 func Query() Matcher {
 	return MatchFunc(func(value driver.Value) bool { return value == "query" })
 }
+
+// describedMatcher is a Matcher which describes
+// what it expects in argument mismatch errors
+type describedMatcher struct {
+	desc     string
+	match    func(driver.Value) bool
+	children []Matcher
+	captures bool
+}
+
+func (m *describedMatcher) Match(v driver.Value) bool { return m.match(v) }
+
+func (m *describedMatcher) String() string { return m.desc }
+
+// capture forwards the matched argument to the capturing children
+func (m *describedMatcher) capture(v driver.Value) {
+	if !m.captures {
+		return
+	}
+	for _, child := range m.children {
+		if c, ok := child.(capturer); ok {
+			c.capture(v)
+		}
+	}
+}
+
+// capturer is implemented by matchers recording the argument they
+// matched, capture is only called once the whole call matched the
+// expectation, so that candidates which eventually do not match
+// leave the destination untouched
+type capturer interface {
+	capture(v driver.Value)
+}
+
+// describeMatcher returns the description of a matcher
+// or its type if it does not describe itself
+func describeMatcher(m Matcher) string {
+	if s, ok := m.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%T", m)
+}
+
+func describeMatchers(matchers []Matcher) string {
+	descs := make([]string, len(matchers))
+	for i, m := range matchers {
+		descs[i] = describeMatcher(m)
+	}
+	return strings.Join(descs, ", ")
+}
+
+// driverValue converts an expected value the way the
+// default converter converts actual arguments
+func driverValue(v interface{}) driver.Value {
+	if dv, err := driver.DefaultParameterConverter.ConvertValue(v); err == nil {
+		return dv
+	}
+	return v
+}
+
+// stringValue returns the text of string or []byte values
+func stringValue(v driver.Value) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	}
+	return "", false
+}
+
+// Eq matches arguments equal to v, once converted to a driver value,
+// string and []byte values are compared by their content.
+func Eq(v interface{}) Matcher {
+	expected := driverValue(v)
+	return &describedMatcher{
+		desc: fmt.Sprintf("Eq(%+v)", v),
+		match: func(actual driver.Value) bool {
+			if s, ok := stringValue(expected); ok {
+				a, ok := stringValue(actual)
+				return ok && a == s
+			}
+			return reflect.DeepEqual(expected, actual)
+		},
+	}
+}
+
+// Not matches arguments the given matcher does not match.
+func Not(m Matcher) Matcher {
+	return &describedMatcher{
+		desc:  fmt.Sprintf("Not(%s)", describeMatcher(m)),
+		match: func(v driver.Value) bool { return !m.Match(v) },
+	}
+}
+
+// AnyOf matches arguments matched by at least one of the matchers.
+func AnyOf(matchers ...Matcher) Matcher {
+	return &describedMatcher{
+		desc: fmt.Sprintf("AnyOf(%s)", describeMatchers(matchers)),
+		match: func(v driver.Value) bool {
+			for _, m := range matchers {
+				if m.Match(v) {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// AllOf matches arguments matched by every one of the matchers,
+// it may combine a Capture with the matchers checking the argument.
+func AllOf(matchers ...Matcher) Matcher {
+	return &describedMatcher{
+		desc: fmt.Sprintf("AllOf(%s)", describeMatchers(matchers)),
+		match: func(v driver.Value) bool {
+			for _, m := range matchers {
+				if !m.Match(v) {
+					return false
+				}
+			}
+			return true
+		},
+		children: matchers,
+		captures: true,
+	}
+}
+
+// Regex matches string or []byte arguments against a regular
+// expression, it panics if the expression cannot be compiled.
+func Regex(expr string) Matcher {
+	re := regexp.MustCompile(expr)
+	return &describedMatcher{
+		desc: fmt.Sprintf("Regex(%q)", expr),
+		match: func(v driver.Value) bool {
+			s, ok := stringValue(v)
+			return ok && re.MatchString(s)
+		},
+	}
+}
+
+// Contains matches string or []byte arguments containing substr.
+func Contains(substr string) Matcher {
+	return &describedMatcher{
+		desc: fmt.Sprintf("Contains(%q)", substr),
+		match: func(v driver.Value) bool {
+			s, ok := stringValue(v)
+			return ok && strings.Contains(s, substr)
+		},
+	}
+}
+
+// HasPrefix matches string or []byte arguments starting with prefix.
+func HasPrefix(prefix string) Matcher {
+	return &describedMatcher{
+		desc: fmt.Sprintf("HasPrefix(%q)", prefix),
+		match: func(v driver.Value) bool {
+			s, ok := stringValue(v)
+			return ok && strings.HasPrefix(s, prefix)
+		},
+	}
+}
+
+// Between matches numeric, string or time.Time arguments
+// within the inclusive range from min to max.
+func Between(min, max interface{}) Matcher {
+	lo, hi := driverValue(min), driverValue(max)
+	return &describedMatcher{
+		desc: fmt.Sprintf("Between(%+v, %+v)", min, max),
+		match: func(v driver.Value) bool {
+			c1, ok1 := compareValues(v, lo)
+			c2, ok2 := compareValues(v, hi)
+			return ok1 && ok2 && c1 >= 0 && c2 <= 0
+		},
+	}
+}
+
+// compareValues orders two driver values of comparable kinds
+func compareValues(a, b driver.Value) (int, bool) {
+	if ta, ok := a.(time.Time); ok {
+		tb, ok := b.(time.Time)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case ta.Before(tb):
+			return -1, true
+		case ta.After(tb):
+			return 1, true
+		}
+		return 0, true
+	}
+
+	if sa, ok := stringValue(a); ok {
+		sb, ok := stringValue(b)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(sa, sb), true
+	}
+
+	fa, ok1 := floatValue(a)
+	fb, ok2 := floatValue(b)
+	if !ok1 || !ok2 {
+		return 0, false
+	}
+	switch {
+	case fa < fb:
+		return -1, true
+	case fa > fb:
+		return 1, true
+	}
+	return 0, true
+}
+
+func floatValue(v driver.Value) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// TimeNear matches time.Time arguments within tolerance of t,
+// useful for timestamps set by the code under test.
+func TimeNear(t time.Time, tolerance time.Duration) Matcher {
+	return &describedMatcher{
+		desc: fmt.Sprintf("TimeNear(%s, %s)", t.Format(time.RFC3339Nano), tolerance),
+		match: func(v driver.Value) bool {
+			actual, ok := v.(time.Time)
+			if !ok {
+				return false
+			}
+			d := actual.Sub(t)
+			return d <= tolerance && d >= -tolerance
+		},
+	}
+}
+
+// TypeOf matches arguments of the same driver value type as v,
+// for example TypeOf(0) matches any int64 argument.
+func TypeOf(v interface{}) Matcher {
+	typ := reflect.TypeOf(driverValue(v))
+	return &describedMatcher{
+		desc:  fmt.Sprintf("TypeOf(%s)", typ),
+		match: func(actual driver.Value) bool { return reflect.TypeOf(actual) == typ },
+	}
+}
+
+// IsNil matches NULL arguments.
+func IsNil() Matcher {
+	return &describedMatcher{
+		desc:  "IsNil()",
+		match: func(v driver.Value) bool { return v == nil },
+	}
+}
+
+// JSONEq matches string or []byte arguments holding a JSON document
+// equivalent to the expected one, regardless of formatting or key order.
+func JSONEq(expected string) Matcher {
+	var want interface{}
+	wantErr := json.Unmarshal([]byte(expected), &want)
+	return &describedMatcher{
+		desc: fmt.Sprintf("JSONEq(%s)", expected),
+		match: func(v driver.Value) bool {
+			s, ok := stringValue(v)
+			if !ok || wantErr != nil {
+				return false
+			}
+			var got interface{}
+			if err := json.Unmarshal([]byte(s), &got); err != nil {
+				return false
+			}
+			return reflect.DeepEqual(want, got)
+		},
+	}
+}
+
+// Len matches string, []byte or other slice arguments of length n.
+func Len(n int) Matcher {
+	return &describedMatcher{
+		desc: fmt.Sprintf("Len(%d)", n),
+		match: func(v driver.Value) bool {
+			rv := reflect.ValueOf(v)
+			switch rv.Kind() {
+			case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+				return rv.Len() == n
+			}
+			return false
+		},
+	}
+}
+
+type captureMatcher struct {
+	dest reflect.Value
+}
+
+// Capture matches any argument which can be stored in dest, a non nil
+// pointer, and stores it there once the call matched the expectation.
+// Combine it with AllOf to check the captured argument as well.
+func Capture(dest interface{}) Matcher {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		panic(fmt.Sprintf("Capture expects a non nil pointer, but got %T", dest))
+	}
+	return &captureMatcher{dest: rv.Elem()}
+}
+
+func (m *captureMatcher) Match(v driver.Value) bool {
+	if v == nil {
+		switch m.dest.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+			return true
+		}
+		return false
+	}
+	return reflect.TypeOf(v).AssignableTo(m.dest.Type())
+}
+
+func (m *captureMatcher) capture(v driver.Value) {
+	if !m.Match(v) {
+		return
+	}
+	if v == nil {
+		m.dest.Set(reflect.Zero(m.dest.Type()))
+		return
+	}
+	m.dest.Set(reflect.ValueOf(v))
+}
+
+func (m *captureMatcher) String() string {
+	return fmt.Sprintf("Capture(*%s)", m.dest.Type())
+}
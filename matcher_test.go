@@ -2,6 +2,7 @@ package sqlmock
 
 import (
 	"database/sql/driver"
+	"strings"
 	"testing"
 	"time"
 )
@@ -56,3 +57,95 @@ func TestByteSliceMatcher(t *testing.T) {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 }
+
+func TestMatchers(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		matcher Matcher
+		desc    string
+		match   []driver.Value
+		noMatch []driver.Value
+	}{
+		{Eq(5), "Eq(5)", []driver.Value{int64(5)}, []driver.Value{int64(6), "5"}},
+		{Eq("john"), "Eq(john)", []driver.Value{"john", []byte("john")}, []driver.Value{"jane"}},
+		{Not(Eq(5)), "Not(Eq(5))", []driver.Value{int64(6)}, []driver.Value{int64(5)}},
+		{AnyOf(Eq(1), Eq(2)), "AnyOf(Eq(1), Eq(2))", []driver.Value{int64(1), int64(2)}, []driver.Value{int64(3)}},
+		{AllOf(TypeOf(0), Between(1, 3)), "AllOf(TypeOf(int64), Between(1, 3))", []driver.Value{int64(1), int64(3)}, []driver.Value{int64(4), 2.0}},
+		{Regex("^[a-z]+@example.com$"), `Regex("^[a-z]+@example.com$")`, []driver.Value{"john@example.com"}, []driver.Value{"john@example.org", int64(1)}},
+		{Contains("oh"), `Contains("oh")`, []driver.Value{"john", []byte("john")}, []driver.Value{"jane"}},
+		{HasPrefix("jo"), `HasPrefix("jo")`, []driver.Value{"john"}, []driver.Value{"ajo"}},
+		{Between(1.5, 2.5), "Between(1.5, 2.5)", []driver.Value{int64(2), 2.5}, []driver.Value{int64(3), "2"}},
+		{Between(now.Add(-time.Hour), now), "", []driver.Value{now.Add(-time.Minute)}, []driver.Value{now.Add(time.Minute)}},
+		{TimeNear(now, time.Second), "", []driver.Value{now.Add(500 * time.Millisecond)}, []driver.Value{now.Add(-2 * time.Second), "now"}},
+		{IsNil(), "IsNil()", []driver.Value{nil}, []driver.Value{int64(0), ""}},
+		{JSONEq(`{"a": 1, "b": [true]}`), `JSONEq({"a": 1, "b": [true]})`, []driver.Value{`{"b":[true],"a":1}`, []byte(`{"a":1,"b":[true]}`)}, []driver.Value{`{"a":2,"b":[true]}`, "{"}},
+		{Len(3), "Len(3)", []driver.Value{"abc", []byte{1, 2, 3}}, []driver.Value{"ab", int64(3)}},
+	}
+
+	for _, c := range cases {
+		desc := describeMatcher(c.matcher)
+		if c.desc != "" && desc != c.desc {
+			t.Errorf("expected matcher description %s, but got %s", c.desc, desc)
+		}
+		for _, v := range c.match {
+			if !c.matcher.Match(v) {
+				t.Errorf("%s should match %T - %+v", desc, v, v)
+			}
+		}
+		for _, v := range c.noMatch {
+			if c.matcher.Match(v) {
+				t.Errorf("%s should not match %T - %+v", desc, v, v)
+			}
+		}
+	}
+}
+
+func TestMatcherDescriptionInArgsMismatch(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO users").
+		WithArgs(HasPrefix("jo")).
+		WillReturnResult(NewResult(1, 1))
+
+	_, err = db.Exec("INSERT INTO users(name) VALUES (?)", "jane")
+	if err == nil || !strings.Contains(err.Error(), `matcher HasPrefix("jo") could not match 0 argument`) {
+		t.Errorf("expected the matcher description in the error, but got: %v", err)
+	}
+}
+
+func TestCaptureMatcher(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.MatchExpectationsInOrder(false)
+
+	var rejected, name string
+	var id int64
+	mock.ExpectExec("INSERT INTO users").
+		WithArgs(Capture(&rejected), Eq(2)).
+		WillReturnResult(NewResult(2, 1))
+	mock.ExpectExec("INSERT INTO users").
+		WithArgs(AllOf(Contains("o"), Capture(&name)), Capture(&id)).
+		WillReturnResult(NewResult(1, 1))
+
+	_, err = db.Exec("INSERT INTO users(name, id) VALUES (?, ?)", "john", 1)
+	if err != nil {
+		t.Errorf("error '%s' was not expected, while inserting a row", err)
+	}
+
+	if name != "john" || id != 1 {
+		t.Errorf("expected captured arguments john and 1, but got %q and %d", name, id)
+	}
+	if rejected != "" {
+		t.Errorf("expected no capture by a candidate which did not match, but got %q", rejected)
+	}
+	if desc := describeMatcher(Capture(&id)); desc != "Capture(*int64)" {
+		t.Errorf("unexpected capture description: %s", desc)
+	}
+}
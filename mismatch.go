@@ -0,0 +1,372 @@
+//go:build go1.8
+// +build go1.8
+
+package sqlmock
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// MismatchError is returned when a Query or Exec call does not match
+// any pending sql expectation. It lists the pending expectations from
+// the closest to the furthest, with what differed for each of them.
+type MismatchError struct {
+	// Call is the mocked call, "Query" or "ExecQuery".
+	Call string
+
+	// Query and Args are the sql and arguments of the call.
+	Query string
+	Args  []driver.NamedValue
+
+	// Candidates are the pending expectations which did not
+	// match the call, ranked by similarity, closest first.
+	Candidates []*MismatchCandidate
+
+	msg string
+}
+
+// MismatchCandidate describes why a pending sql
+// expectation did not match a call.
+type MismatchCandidate struct {
+	// Expectation is the expectation which did not match.
+	Expectation *ExpectedSql
+
+	// Similarity ranks the candidate, from 0 when nothing
+	// matches up to 1 when everything but the tx or the
+	// connection matches.
+	Similarity float64
+
+	// Reason is the first check which failed.
+	Reason string
+
+	// SQLDiff is a unified diff of the expected and the actual sql,
+	// both normalized, empty when the sql matches.
+	SQLDiff string
+
+	// Args compares every expected argument to the actual one,
+	// it is empty when arguments are checked by WithArgsCheck.
+	Args []ArgDiff
+
+	err error
+}
+
+// ArgDiff compares an expected argument with the actual one.
+type ArgDiff struct {
+	// Arg is the position of the argument, or its name prefixed by ":".
+	Arg string
+
+	// Expected and Actual describe the argument values,
+	// "<missing>" when there is no such argument.
+	Expected string
+	Actual   string
+
+	Match bool
+}
+
+// maxCandidates is the number of candidates detailed by Error
+const maxCandidates = 3
+
+func (e *MismatchError) Error() string {
+	if len(e.Candidates) == 0 {
+		return e.msg
+	}
+
+	var b strings.Builder
+	b.WriteString(e.msg)
+	b.WriteString("\n  closest pending expectations:")
+	for i, cand := range e.Candidates {
+		if i == maxCandidates {
+			fmt.Fprintf(&b, "\n  ... and %d more", len(e.Candidates)-maxCandidates)
+			break
+		}
+
+		header := strings.SplitN(cand.Expectation.String(), "\n", 2)[0]
+		fmt.Fprintf(&b, "\n  %d) %s (similarity %.0f%%)", i+1, header, cand.Similarity*100)
+		fmt.Fprintf(&b, "\n     reason: %s", indent(cand.Reason, "     "))
+		if cand.SQLDiff != "" {
+			b.WriteString("\n     sql diff:\n")
+			b.WriteString(indent("       "+cand.SQLDiff, "       "))
+		}
+		if len(cand.Args) > 0 {
+			b.WriteString("\n     arguments:\n")
+			b.WriteString(indent("       "+formatArgDiffs(cand.Args), "       "))
+		}
+	}
+	return b.String()
+}
+
+// Is reports the mismatch as an ErrUnexpectedCall.
+func (e *MismatchError) Is(target error) bool {
+	return target == ErrUnexpectedCall
+}
+
+// Unwrap returns the reason of the closest candidate, so that
+// errors.As finds an *ArgsMismatchError when only arguments differ.
+func (e *MismatchError) Unwrap() error {
+	if len(e.Candidates) == 0 {
+		return nil
+	}
+	return e.Candidates[0].err
+}
+
+// newMismatchError ranks the candidates, closest first
+func newMismatchError(msg, call, query string, args []driver.NamedValue, candidates []*MismatchCandidate) *MismatchError {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Similarity > candidates[j].Similarity
+	})
+	return &MismatchError{
+		Call:       call,
+		Query:      query,
+		Args:       args,
+		Candidates: candidates,
+		msg:        msg,
+	}
+}
+
+// rejection is a pending sql expectation which did not match a call
+type rejection struct {
+	e   *ExpectedSql
+	err error
+}
+
+// candidates describes how a call differs from every rejected expectation,
+// it is only done once no expectation matched the call
+func (c *conn) candidates(rejected []rejection, stmt *statement, opt, query string, args []driver.NamedValue) []*MismatchCandidate {
+	candidates := make([]*MismatchCandidate, len(rejected))
+	for i, r := range rejected {
+		r.e.Lock()
+		candidates[i] = c.mismatch(r.e, r.err, stmt, opt, query, args)
+		r.e.Unlock()
+	}
+	return candidates
+}
+
+// mismatch describes how a call differs from a sql expectation,
+// the caller holds the expectation lock
+func (c *conn) mismatch(e *ExpectedSql, reason error, stmt *statement, opt, query string, args []driver.NamedValue) *MismatchCandidate {
+	cand := &MismatchCandidate{Expectation: e, Reason: reason.Error(), err: reason}
+
+	sqlScore := 1.0
+	if c.queryMatcher.Match(e.expectSQL, query) != nil {
+		expected := unescapeSQL(stripQuery(e.expectSQL))
+		actual := stripQuery(query)
+		cand.SQLDiff = sqlDiff(expected, actual)
+		sqlScore = similarity(strings.Fields(expected), strings.Fields(actual))
+	}
+
+	// the user's check is not run again, the arguments failed only when
+	// sqlMatches returned an *ArgsMismatchError, an earlier failure left
+	// them unchecked
+	argScore := 1.0
+	if e.checkArgs != nil {
+		var argsErr *ArgsMismatchError
+		if errors.As(reason, &argsErr) {
+			argScore = 0
+		}
+	} else {
+		cand.Args = e.diffArgs(args)
+		if len(cand.Args) > 0 {
+			var matched int
+			for _, arg := range cand.Args {
+				if arg.Match {
+					matched++
+				}
+			}
+			argScore = float64(matched) / float64(len(cand.Args))
+		}
+	}
+
+	opScore, stmtScore := 1.0, 1.0
+	if !e.matchesOp(opt) {
+		opScore = 0
+	}
+	if e.checkStmt(stmt, "the call") != nil {
+		stmtScore = 0
+	}
+
+	cand.Similarity = 0.5*sqlScore + 0.3*argScore + 0.1*opScore + 0.1*stmtScore
+	return cand
+}
+
+// diffArgs compares the expected arguments with the actual ones
+func (e *queryBasedExpectation) diffArgs(args []driver.NamedValue) []ArgDiff {
+	if e.namedArgs == nil {
+		n := len(args)
+		if len(e.args) > n {
+			n = len(e.args)
+		}
+
+		diffs := make([]ArgDiff, n)
+		for i := range diffs {
+			diff := ArgDiff{Arg: fmt.Sprint(i), Expected: "<missing>", Actual: "<missing>"}
+			if i < len(e.args) {
+				diff.Expected = describeArg(e.args[i])
+			}
+			if i < len(args) {
+				diff.Actual = describeNamedValue(args[i])
+			}
+			if i < len(e.args) && i < len(args) {
+				diff.Match = e.argMatches(e.args[i], args[i])
+			}
+			diffs[i] = diff
+		}
+		return diffs
+	}
+
+	var positional []driver.NamedValue
+	named := make(map[string]driver.NamedValue)
+	for _, arg := range args {
+		if arg.Name == "" {
+			positional = append(positional, arg)
+			continue
+		}
+		named[arg.Name] = arg
+	}
+
+	diffs := (&queryBasedExpectation{args: e.args, converter: e.converter}).diffArgs(positional)
+	names := sortedNames(e.namedArgs)
+	for _, name := range sortedNames(named) {
+		if _, ok := e.namedArgs[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	for _, name := range names {
+		diff := ArgDiff{Arg: ":" + name, Expected: "<missing>", Actual: "<missing>"}
+		expected, isExpected := e.namedArgs[name]
+		actual, isActual := named[name]
+		if isExpected {
+			diff.Expected = describeArg(expected)
+		}
+		if isActual {
+			diff.Actual = describeArg(actual.Value)
+		}
+		if isExpected && isActual {
+			diff.Match = e.argMatches(expected, driver.NamedValue{Value: actual.Value})
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs
+}
+
+// argMatches checks a single argument the way argsMatches does
+func (e *queryBasedExpectation) argMatches(expected driver.Value, actual driver.NamedValue) bool {
+	if matcher, ok := expected.(Matcher); ok {
+		return matcher.Match(actual.Value)
+	}
+	if named, ok := expected.(sql.NamedArg); ok {
+		if named.Name != actual.Name {
+			return false
+		}
+		expected = named.Value
+	}
+	return e.valueMatches("argument", expected, actual.Value) == nil
+}
+
+func describeArg(v driver.Value) string {
+	switch v := v.(type) {
+	case Matcher:
+		return describeMatcher(v)
+	case sql.NamedArg:
+		return fmt.Sprintf(":%s = %s", v.Name, describeArg(v.Value))
+	}
+	return fmt.Sprintf("%T - %+v", v, v)
+}
+
+func describeNamedValue(v driver.NamedValue) string {
+	if v.Name != "" {
+		return fmt.Sprintf(":%s = %T - %+v", v.Name, v.Value, v.Value)
+	}
+	return fmt.Sprintf("%T - %+v", v.Value, v.Value)
+}
+
+func formatArgDiffs(diffs []ArgDiff) string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "arg\texpected\tactual\tmatch")
+	for _, d := range diffs {
+		status := "ok"
+		if !d.Match {
+			status = "MISMATCH"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", d.Arg, d.Expected, d.Actual, status)
+	}
+	w.Flush()
+	return strings.TrimRight(buf.String(), "\n")
+}
+
+var escaped = regexp.MustCompile(`\\([^\w\s])`)
+
+// unescapeSQL removes the escaping of an expected sql regular expression
+func unescapeSQL(q string) string {
+	return escaped.ReplaceAllString(q, "$1")
+}
+
+var clauses = regexp.MustCompile(`(?i)\s+((?:(?:LEFT|RIGHT|INNER|OUTER|CROSS)\s+)?JOIN|FROM|WHERE|AND|OR|GROUP BY|ORDER BY|HAVING|LIMIT|OFFSET|VALUES|SET|ON|RETURNING|UNION)\b`)
+
+// sqlDiff returns a unified diff of two normalized queries,
+// split in lines at every clause
+func sqlDiff(expected, actual string) string {
+	a := strings.Split(clauses.ReplaceAllString(expected, "\n$1"), "\n")
+	b := strings.Split(clauses.ReplaceAllString(actual, "\n$1"), "\n")
+
+	lines := []string{"--- expected", "+++ actual"}
+	lengths := lcs(a, b)
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, " "+a[i])
+			i++
+			j++
+		case j == len(b) || i < len(a) && lengths[i+1][j] >= lengths[i][j+1]:
+			lines = append(lines, "-"+a[i])
+			i++
+		default:
+			lines = append(lines, "+"+b[j])
+			j++
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// similarity is the ratio of common tokens, from 0 to 1
+func similarity(a, b []string) float64 {
+	if len(a)+len(b) == 0 {
+		return 1
+	}
+	return 2 * float64(lcs(a, b)[0][0]) / float64(len(a)+len(b))
+}
+
+// lcs returns the lengths of the longest common subsequences
+// of every suffix of a and b
+func lcs(a, b []string) [][]int {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+	return lengths
+}
+
+// indent prefixes every line but the first
+func indent(s, prefix string) string {
+	return strings.Replace(s, "\n", "\n"+prefix, -1)
+}
@@ -0,0 +1,182 @@
+//go:build go1.8
+// +build go1.8
+
+package sqlmock
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMismatchErrorRanksCandidates(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.MatchExpectationsInOrder(false)
+
+	mock.ExpectExec("DELETE FROM users").WithArgs(1).WillReturnResult(NewResult(0, 1))
+	mock.ExpectQuery("SELECT id, name FROM users WHERE id = \\?").
+		WithArgs(1, "active").
+		WillReturnRows(NewRows([]string{"id", "name"}))
+	mock.ExpectQuery("SELECT id FROM accounts").WillReturnRows(NewRows([]string{"id"}))
+
+	_, err = db.Query("SELECT id, email FROM users WHERE id = ?", 1)
+
+	var mismatch *MismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected a *MismatchError, but got %T: %v", err, err)
+	}
+	if mismatch.Call != "Query" || mismatch.Query != "SELECT id, email FROM users WHERE id = ?" || len(mismatch.Args) != 1 {
+		t.Errorf("unexpected call in mismatch: %s '%s' %+v", mismatch.Call, mismatch.Query, mismatch.Args)
+	}
+	if len(mismatch.Candidates) != 3 {
+		t.Fatalf("expected 3 candidates, but got %d", len(mismatch.Candidates))
+	}
+
+	closest := mismatch.Candidates[0]
+	if closest.Expectation.expectSQL != "SELECT id, name FROM users WHERE id = \\?" {
+		t.Errorf("expected the users query to be the closest candidate, but got: %s", closest.Expectation)
+	}
+	for i := 1; i < len(mismatch.Candidates); i++ {
+		if mismatch.Candidates[i].Similarity > mismatch.Candidates[i-1].Similarity {
+			t.Errorf("expected candidates ranked by similarity, but got %v before %v", mismatch.Candidates[i-1].Similarity, mismatch.Candidates[i].Similarity)
+		}
+	}
+
+	wantDiff := "--- expected\n+++ actual\n-SELECT id, name\n+SELECT id, email\n FROM users\n WHERE id = ?"
+	if closest.SQLDiff != wantDiff {
+		t.Errorf("expected sql diff:\n%s\nbut got:\n%s", wantDiff, closest.SQLDiff)
+	}
+
+	wantArgs := []ArgDiff{
+		{Arg: "0", Expected: "int - 1", Actual: "int64 - 1", Match: true},
+		{Arg: "1", Expected: "string - active", Actual: "<missing>"},
+	}
+	if len(closest.Args) != len(wantArgs) {
+		t.Fatalf("expected argument diffs %+v, but got %+v", wantArgs, closest.Args)
+	}
+	for i, want := range wantArgs {
+		if closest.Args[i] != want {
+			t.Errorf("expected argument diff %+v, but got %+v", want, closest.Args[i])
+		}
+	}
+
+	msg := err.Error()
+	for _, want := range []string{
+		"call to Query 'SELECT id, email FROM users WHERE id = ?' with args [{Name: Ordinal:1 Value:1}] was not expected",
+		"1) ExpectedQuery => expecting Query, QueryContext or QueryRow which:",
+		"+SELECT id, email",
+		"MISMATCH",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected the error to contain %q, but got:\n%s", want, msg)
+		}
+	}
+}
+
+func TestMismatchErrorOrdered(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE users").WithArgs(Eq("john"), 1).WillReturnResult(NewResult(0, 1))
+
+	_, err = db.Exec("UPDATE users SET name = ? WHERE id = ?", "jane", 1)
+
+	var mismatch *MismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected a *MismatchError, but got %T: %v", err, err)
+	}
+	if len(mismatch.Candidates) != 1 {
+		t.Fatalf("expected the next expectation as the only candidate, but got %d", len(mismatch.Candidates))
+	}
+
+	cand := mismatch.Candidates[0]
+	if cand.SQLDiff != "" {
+		t.Errorf("expected no sql diff for a matching query, but got:\n%s", cand.SQLDiff)
+	}
+	if !strings.HasPrefix(cand.Reason, "arguments do not match") {
+		t.Errorf("unexpected reason: %s", cand.Reason)
+	}
+	if cand.Args[0].Match || cand.Args[0].Expected != "Eq(john)" || !cand.Args[1].Match {
+		t.Errorf("unexpected argument diffs: %+v", cand.Args)
+	}
+	if !strings.Contains(err.Error(), "next expectation does not match") {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestMismatchErrorRunsArgsCheckOnce(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	var checks int
+	mock.ExpectExec("DELETE FROM users").
+		WithArgsCheck(func(args []driver.Value) error {
+			checks++
+			return errors.New("no user 2")
+		}).
+		WillReturnResult(NewResult(0, 1))
+
+	_, err = db.Exec("DELETE FROM users", 2)
+
+	var mismatch *MismatchError
+	if !errors.As(err, &mismatch) || len(mismatch.Candidates) != 1 {
+		t.Fatalf("expected a *MismatchError with 1 candidate, but got: %v", err)
+	}
+	if checks != 1 {
+		t.Errorf("expected the arguments to be checked once, but they were checked %d times", checks)
+	}
+	if mismatch.Candidates[0].Similarity != 0.7 {
+		t.Errorf("expected the failed arguments to lower the similarity, but got: %v", mismatch.Candidates[0].Similarity)
+	}
+
+	// the arguments are not checked when the sql differs
+	_, _ = db.Exec("DELETE FROM accounts", 2)
+	if checks != 1 {
+		t.Errorf("expected the arguments not to be checked again, but they were checked %d times", checks)
+	}
+}
+
+func TestMismatchOnlyDescribedWhenNothingMatches(t *testing.T) {
+	var matches int
+	matcher := QueryMatcherFunc(func(expectedSQL, actualSQL string) error {
+		matches++
+		return QueryMatcherEqual.Match(expectedSQL, actualSQL)
+	})
+	db, mock, err := New(QueryMatcherOption(matcher))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.MatchExpectationsInOrder(false)
+
+	mock.ExpectExec("DELETE FROM accounts").WillReturnResult(NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM users").WillReturnResult(NewResult(0, 1))
+
+	if _, err := db.Exec("DELETE FROM users"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if matches != 2 {
+		t.Errorf("expected the sql to be matched once per expectation, but it was matched %d times", matches)
+	}
+
+	matches = 0
+	_, err = db.Exec("DELETE FROM orders")
+	var mismatch *MismatchError
+	if !errors.As(err, &mismatch) || len(mismatch.Candidates) != 1 {
+		t.Fatalf("expected a *MismatchError with 1 candidate, but got: %v", err)
+	}
+	if matches != 2 {
+		t.Errorf("expected the sql to be matched again only to describe the mismatch, but it was matched %d times", matches)
+	}
+}
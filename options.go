@@ -1,6 +1,9 @@
 package sqlmock
 
-import "database/sql/driver"
+import (
+	"database/sql"
+	"database/sql/driver"
+)
 
 // ValueConverterOption allows to create a sqlmock connection
 // with a custom ValueConverter to support drivers with special data types.
@@ -36,3 +39,40 @@ func MonitorPingsOption(monitorPings bool) func(*sqlmock) error {
 		return nil
 	}
 }
+
+// SupportedIsolationLevelsOption restricts the transaction isolation levels
+// the mock driver accepts. BeginTx with any other level, except the default
+// one, fails like a real driver would, without matching an expectation.
+//
+// If this option is omitted, any isolation level is accepted.
+func SupportedIsolationLevelsOption(levels ...sql.IsolationLevel) func(*sqlmock) error {
+	return func(s *sqlmock) error {
+		s.isolationLevels = make(map[driver.IsolationLevel]bool, len(levels))
+		for _, level := range levels {
+			s.isolationLevels[driver.IsolationLevel(level)] = true
+		}
+		return nil
+	}
+}
+
+// StrictOption reports every unexpected call as soon as it happens,
+// so that a test fails even when the code under test swallows the
+// error it was returned. The call is reported with t.Errorf, or with
+// a panic when t is nil.
+//
+// Without this option, unexpected calls are only reported
+// by ExpectationsWereMet.
+func StrictOption(t TestingT) func(*sqlmock) error {
+	return func(s *sqlmock) error {
+		s.strict = func(call UnexpectedCall) {
+			if t == nil {
+				panic("sqlmock: " + call.String())
+			}
+			if h, ok := t.(interface{ Helper() }); ok {
+				h.Helper()
+			}
+			t.Errorf("sqlmock: %s", call)
+		}
+		return nil
+	}
+}
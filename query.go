@@ -3,6 +3,7 @@ package sqlmock
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -66,3 +67,65 @@ var QueryMatcherEqual QueryMatcher = QueryMatcherFunc(func(expectedSQL, actualSQ
 	}
 	return nil
 })
+
+// countPlaceholders counts the arguments an SQL query takes, using
+// "?" positional, "$N" numbered and ":name" or "@name" named
+// placeholders. Quoted strings, identifiers and comments are skipped,
+// as well as "::" type casts and "@@" system variables.
+func countPlaceholders(query string) int {
+	var positional, numbered int
+	named := make(map[string]bool)
+
+	isName := func(c byte) bool {
+		return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+	}
+
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			// skip the quoted literal, doubled quotes escape themselves
+			for i++; i < len(query) && query[i] != c; i++ {
+			}
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			for ; i < len(query) && query[i] != '\n'; i++ {
+			}
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return positional + numbered + len(named)
+			}
+			i += end + 3
+		case c == '?':
+			positional++
+		case c == '$':
+			j := i + 1
+			for ; j < len(query) && query[j] >= '0' && query[j] <= '9'; j++ {
+			}
+			if j > i+1 {
+				if n, _ := strconv.Atoi(query[i+1 : j]); n > numbered {
+					numbered = n
+				}
+			}
+			i = j - 1
+		case c == ':' || c == '@':
+			if i+1 < len(query) && query[i+1] == c {
+				i++ // "::" cast or "@@" variable
+				for ; i+1 < len(query) && isName(query[i+1]); i++ {
+				}
+				continue
+			}
+			if i > 0 && isName(query[i-1]) {
+				continue // such as a time literal or an email
+			}
+			j := i + 1
+			for ; j < len(query) && isName(query[j]); j++ {
+			}
+			if j > i+1 {
+				named[query[i+1:j]] = true
+			}
+			i = j - 1
+		}
+	}
+	return positional + numbered + len(named)
+}
@@ -2,6 +2,7 @@ package sqlmock
 
 import (
 	"fmt"
+	"testing"
 )
 
 func ExampleQueryMatcher() {
@@ -39,3 +40,28 @@ func ExampleQueryMatcher() {
 	// Output: scanned id: 1 and title: one
 	// scanned id: 2 and title: two
 }
+
+func TestCountPlaceholders(t *testing.T) {
+	cases := []struct {
+		query string
+		want  int
+	}{
+		{"SELECT * FROM users", 0},
+		{"SELECT * FROM users WHERE id = ? AND name = ?", 2},
+		{"UPDATE users SET name = $2 WHERE id = $1", 2},
+		{"SELECT * FROM users WHERE id = $1 OR parent = $1", 1},
+		{"SELECT * FROM users WHERE id = :id AND name = :name OR alias = :name", 2},
+		{"SELECT * FROM users WHERE id = @id", 1},
+		{"SELECT id::text FROM users WHERE id = :id", 1},
+		{"SELECT @@version, ? FROM dual", 1},
+		{"SELECT '?', \"$1\", `:col` FROM users WHERE id = ?", 1},
+		{"SELECT 1 -- is it ?\nFROM users WHERE id = ? /* or :id */", 1},
+		{"SELECT * FROM users WHERE email = 'john@example.com' AND id = ?", 1},
+	}
+
+	for _, c := range cases {
+		if got := countPlaceholders(c.query); got != c.want {
+			t.Errorf("expected %d placeholders in %q, but got %d", c.want, c.query, got)
+		}
+	}
+}
@@ -2,10 +2,11 @@ package sqlmock
 
 import (
 	"bytes"
+	"context"
 	"database/sql/driver"
 	"fmt"
-	"io"
 	"strings"
+	"time"
 )
 
 const invalidate = "☠☠☠ MEMORY OVERWRITTEN ☠☠☠ "
@@ -15,6 +16,22 @@ type rowSets struct {
 	pos  int
 	ex   *ExpectedSql
 	raw  [][]byte
+	ctx  context.Context
+
+	// closed is guarded by the lock of the expectation
+	closed bool
+}
+
+// rewind returns a copy of the row sets with every cursor reset,
+// so an expectation triggered more than once returns all rows each time
+func (rs *rowSets) rewind() *rowSets {
+	sets := make([]*Rows, len(rs.sets))
+	for i, set := range rs.sets {
+		cp := *set
+		cp.pos = 0
+		sets[i] = &cp
+	}
+	return &rowSets{sets: sets, ex: rs.ex, ctx: rs.ctx}
 }
 
 func (rs *rowSets) Columns() []string {
@@ -23,20 +40,26 @@ func (rs *rowSets) Columns() []string {
 
 func (rs *rowSets) Close() error {
 	rs.invalidateRaw()
-	rs.ex.rowsWereClosed = true
+	rs.ex.Lock()
+	rs.closed = true
+	rs.ex.Unlock()
 	return rs.sets[rs.pos].closeErr
 }
 
 // Next advances to next row
 func (rs *rowSets) Next(dest []driver.Value) error {
 	r := rs.sets[rs.pos]
-	r.pos++
 	rs.invalidateRaw()
-	if r.pos > len(r.rows) {
-		return io.EOF // per interface spec
+	if err := rs.await(r.delay(r.pos)); err != nil {
+		return err
 	}
 
-	for i, col := range r.rows[r.pos-1] {
+	row, err := r.next(rs.context())
+	if err != nil {
+		return err
+	}
+
+	for i, col := range row {
 		if b, ok := rawBytes(col); ok {
 			rs.raw = append(rs.raw, b)
 			dest[i] = b
@@ -56,6 +79,9 @@ func (rs *rowSets) String() string {
 
 	msg := "should return rows:\n"
 	if len(rs.sets) == 1 {
+		if rs.sets[0].iter != nil {
+			return "should return rows from an iterator"
+		}
 		for n, row := range rs.sets[0].rows {
 			msg += fmt.Sprintf("    row %d - %+v\n", n, row)
 		}
@@ -63,6 +89,10 @@ func (rs *rowSets) String() string {
 	}
 	for i, set := range rs.sets {
 		msg += fmt.Sprintf("    result set: %d\n", i)
+		if set.iter != nil {
+			msg += "      rows from an iterator\n"
+			continue
+		}
 		for n, row := range set.rows {
 			msg += fmt.Sprintf("      row %d - %+v\n", n, row)
 		}
@@ -72,7 +102,7 @@ func (rs *rowSets) String() string {
 
 func (rs *rowSets) empty() bool {
 	for _, set := range rs.sets {
-		if len(set.rows) > 0 {
+		if len(set.rows) > 0 || set.iter != nil {
 			return false
 		}
 	}
@@ -96,10 +126,16 @@ func (rs *rowSets) invalidateRaw() {
 type Rows struct {
 	converter driver.ValueConverter
 	cols      []string
+	def       []*Column
 	rows      [][]driver.Value
 	pos       int
 	nextErr   map[int]error
 	closeErr  error
+	csv       CSVOptions
+	parsers   map[string]ColumnParser
+	iter      rowSource
+	rowDelay  time.Duration
+	delays    map[int]time.Duration
 }
 
 // NewRows allows Rows to be created from a
@@ -114,6 +150,30 @@ func NewRows(columns []string) *Rows {
 	}
 }
 
+// NewRowsWithColumnDefinition allows Rows to be created with
+// column definitions, which describe the metadata returned by
+// *sql.Rows.ColumnTypes.
+// Use Sqlmock.NewRowsWithColumnDefinition instead if using a custom converter
+func NewRowsWithColumnDefinition(columns ...*Column) *Rows {
+	cols := make([]string, len(columns))
+	for i, col := range columns {
+		cols[i] = col.Name()
+	}
+
+	r := NewRows(cols)
+	r.def = columns
+	return r
+}
+
+// column returns the definition of the column at index, a column
+// without a definition is described by its name only
+func (r *Rows) column(index int) *Column {
+	if index < len(r.def) {
+		return r.def[index]
+	}
+	return NewColumn(r.cols[index])
+}
+
 // CloseError allows to set an error
 // which will be returned by rows.Close
 // function.
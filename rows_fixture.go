@@ -0,0 +1,268 @@
+package sqlmock
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVOptions configures how Rows are read from
+// CSV or TSV fixtures.
+type CSVOptions struct {
+	// Comma is the field delimiter, ',' by default.
+	Comma rune
+
+	// NullToken is the field value read as NULL, "NULL" by default.
+	NullToken string
+
+	// LazyQuotes allows a quote to appear in an unquoted field
+	// and a non-doubled quote to appear in a quoted field.
+	LazyQuotes bool
+
+	// Header tells that the first record holds column names,
+	// fields are then mapped to the Rows columns by name.
+	Header bool
+}
+
+// ColumnParser converts a field read from a fixture to a driver value.
+type ColumnParser func(field string) (driver.Value, error)
+
+// WithCSVOptions sets the options used to read CSV or TSV
+// fixtures into these Rows.
+func (r *Rows) WithCSVOptions(opts CSVOptions) *Rows {
+	r.csv = opts
+	return r
+}
+
+// WithColumnParser sets the function converting fields of the
+// named column read from fixtures. By default fields are kept as
+// []byte, like a text protocol driver would return them, unless
+// the column is defined with a type by NewRowsWithColumnDefinition.
+// The parsed value is then converted with the Rows converter.
+func (r *Rows) WithColumnParser(column string, parse ColumnParser) *Rows {
+	if r.parsers == nil {
+		r.parsers = make(map[string]ColumnParser)
+	}
+	r.parsers[column] = parse
+	return r
+}
+
+// FromCSVString adds rows read from a CSV string, every field
+// is trimmed of surrounding white space. It panics if the CSV
+// cannot be read, same as AddRow does on invalid values.
+func (r *Rows) FromCSVString(s string) *Rows {
+	return r.FromCSVReader(strings.NewReader(strings.TrimSpace(s)))
+}
+
+// FromCSVReader adds rows read as CSV from the given reader,
+// see FromCSVString.
+func (r *Rows) FromCSVReader(rd io.Reader) *Rows {
+	if err := r.readCSV(rd); err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// FromJSON adds rows read from a JSON array of objects, keyed by
+// column name. Missing keys are read as NULL, nested objects and
+// arrays are kept as their JSON encoding.
+func (r *Rows) FromJSON(data []byte) *Rows {
+	if err := r.readJSON(data); err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// FromFile adds rows read from a fixture file, the format is
+// chosen by the file extension: ".csv", ".tsv" or ".json".
+func (r *Rows) FromFile(path string) *Rows {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		panic(err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		err = r.readCSV(bytes.NewReader(data))
+	case ".tsv":
+		if r.csv.Comma == 0 {
+			r.csv.Comma = '\t'
+		}
+		err = r.readCSV(bytes.NewReader(data))
+	case ".json":
+		err = r.readJSON(data)
+	default:
+		err = fmt.Errorf("unsupported fixture file format: %s", path)
+	}
+	if err != nil {
+		panic(fmt.Errorf("fixture %s: %s", path, err))
+	}
+	return r
+}
+
+func (r *Rows) readCSV(rd io.Reader) error {
+	cr := csv.NewReader(rd)
+	cr.LazyQuotes = r.csv.LazyQuotes
+	cr.TrimLeadingSpace = true
+	cr.FieldsPerRecord = -1
+	if r.csv.Comma != 0 {
+		cr.Comma = r.csv.Comma
+	}
+
+	var order []int
+	for record := 1; ; record++ {
+		fields, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if r.csv.Header && order == nil {
+			if order, err = r.columnOrder(fields); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if len(fields) != len(r.cols) {
+			return fmt.Errorf("record %d: expected %d fields to match the number of columns, but got %d", record, len(r.cols), len(fields))
+		}
+
+		row := make([]driver.Value, len(r.cols))
+		for i, field := range fields {
+			col := i
+			if order != nil {
+				col = order[i]
+			}
+			if row[col], err = r.parseField(col, strings.TrimSpace(field)); err != nil {
+				return fmt.Errorf("record %d, column %q: %s", record, r.cols[col], err)
+			}
+		}
+		r.AddRow(row...)
+	}
+}
+
+// columnOrder maps the fields of a header record to the column positions
+func (r *Rows) columnOrder(header []string) ([]int, error) {
+	if len(header) != len(r.cols) {
+		return nil, fmt.Errorf("header: expected %d fields to match the number of columns, but got %d", len(r.cols), len(header))
+	}
+
+	order := make([]int, len(header))
+	for i, name := range header {
+		order[i] = -1
+		for col, c := range r.cols {
+			if c == strings.TrimSpace(name) {
+				order[i] = col
+			}
+		}
+		if order[i] < 0 {
+			return nil, fmt.Errorf("header: unknown column %q", name)
+		}
+	}
+	return order, nil
+}
+
+func (r *Rows) parseField(col int, field string) (driver.Value, error) {
+	null := r.csv.NullToken
+	if null == "" {
+		null = "NULL"
+	}
+	if field == null {
+		return nil, nil
+	}
+
+	if parse, ok := r.parsers[r.cols[col]]; ok {
+		return parse(field)
+	}
+	if col < len(r.def) && r.def[col].scanType != nil {
+		return coerceField(field, r.def[col].scanType)
+	}
+	return []byte(field), nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// coerceField parses a fixture field as the given column scan type
+func coerceField(field string, typ reflect.Type) (driver.Value, error) {
+	if typ == timeType {
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02"} {
+			if t, err := time.Parse(layout, field); err == nil {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("cannot parse %q as time", field)
+	}
+
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(field, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(field, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(field, 64)
+	case reflect.Bool:
+		return strconv.ParseBool(field)
+	case reflect.String:
+		return field, nil
+	}
+	return []byte(field), nil
+}
+
+func (r *Rows) readJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var objects []map[string]interface{}
+	if err := dec.Decode(&objects); err != nil {
+		return err
+	}
+
+	for n, object := range objects {
+		row := make([]driver.Value, len(r.cols))
+		for i, col := range r.cols {
+			v, err := r.jsonValue(i, object[col])
+			if err != nil {
+				return fmt.Errorf("object %d, column %q: %s", n, col, err)
+			}
+			row[i] = v
+		}
+		r.AddRow(row...)
+	}
+	return nil
+}
+
+func (r *Rows) jsonValue(col int, v interface{}) (driver.Value, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if parse, ok := r.parsers[r.cols[col]]; ok {
+			return parse(v)
+		}
+		if col < len(r.def) && r.def[col].scanType != nil {
+			return coerceField(v, r.def[col].scanType)
+		}
+		return v, nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		return v.Float64()
+	case bool:
+		return v, nil
+	default:
+		return json.Marshal(v)
+	}
+}
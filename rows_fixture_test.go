@@ -0,0 +1,115 @@
+package sqlmock
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRowsFromCSVString(t *testing.T) {
+	rs := NewRows([]string{"id", "title", "body"}).FromCSVString(`
+		1, hello world, "some, body"
+		2, NULL, ""
+	`)
+
+	want := [][]driver.Value{
+		{[]byte("1"), []byte("hello world"), []byte("some, body")},
+		{[]byte("2"), nil, []byte("")},
+	}
+	if !reflect.DeepEqual(rs.rows, want) {
+		t.Errorf("expected rows %v, but got %v", want, rs.rows)
+	}
+}
+
+func TestRowsFromCSVReaderWithOptions(t *testing.T) {
+	rs := NewRowsWithColumnDefinition(
+		NewColumn("id").OfType("INT", int64(0)),
+		NewColumn("active").OfType("BOOL", false),
+		NewColumn("name"),
+	).
+		WithCSVOptions(CSVOptions{Comma: ';', NullToken: "-", Header: true}).
+		WithColumnParser("name", func(field string) (driver.Value, error) {
+			return strings.ToUpper(field), nil
+		}).
+		FromCSVReader(strings.NewReader("name;id;active\njohn;1;true\n-;2;false\n"))
+
+	want := [][]driver.Value{
+		{int64(1), true, "JOHN"},
+		{int64(2), false, nil},
+	}
+	if !reflect.DeepEqual(rs.rows, want) {
+		t.Errorf("expected rows %v, but got %v", want, rs.rows)
+	}
+}
+
+func TestRowsFromCSVStringInvalid(t *testing.T) {
+	defer func() {
+		if e := recover(); e == nil || !strings.Contains(e.(error).Error(), "record 2: expected 2 fields") {
+			t.Errorf("expected a panic about the number of fields, but got: %v", e)
+		}
+	}()
+
+	NewRows([]string{"id", "name"}).FromCSVString("1,john\n2")
+}
+
+func TestRowsFromJSON(t *testing.T) {
+	rs := NewRowsWithColumnDefinition(
+		NewColumn("id"),
+		NewColumn("name"),
+		NewColumn("tags"),
+		NewColumn("created_at").OfType("TIMESTAMP", time.Time{}),
+	).FromFile("testdata/users.json")
+
+	want := [][]driver.Value{
+		{int64(1), "john", []byte(`["admin"]`), time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{int64(2), nil, nil, time.Date(2021, 2, 3, 4, 5, 6, 0, time.UTC)},
+	}
+	if !reflect.DeepEqual(rs.rows, want) {
+		t.Errorf("expected rows %v, but got %v", want, rs.rows)
+	}
+}
+
+func TestQueryRowsFromTSVFile(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rs := mock.NewRows([]string{"id", "name", "balance"}).
+		WithCSVOptions(CSVOptions{Header: true, NullToken: `\N`}).
+		FromFile("testdata/users.tsv")
+	mock.ExpectSql(Query(), "SELECT (.+) FROM users").WillReturnRows(rs)
+
+	rows, err := db.Query("SELECT id, name, balance FROM users")
+	if err != nil {
+		t.Fatalf("error '%s' was not expected while retrieving mock rows", err)
+	}
+	defer rows.Close()
+
+	var (
+		ids     []int
+		names   []string
+		balance []sql.NullFloat64
+	)
+	for rows.Next() {
+		var id int
+		var name string
+		var b sql.NullFloat64
+		if err := rows.Scan(&id, &name, &b); err != nil {
+			t.Fatalf("error '%s' was not expected while scanning rows", err)
+		}
+		ids, names, balance = append(ids, id), append(names, name), append(balance, b)
+	}
+
+	if !reflect.DeepEqual(ids, []int{1, 2}) || !reflect.DeepEqual(names, []string{"john", "jane"}) {
+		t.Errorf("unexpected rows scanned: %v %v", ids, names)
+	}
+	if !balance[0].Valid || balance[0].Float64 != 10.5 || balance[1].Valid {
+		t.Errorf("unexpected balances scanned: %+v", balance)
+	}
+}
@@ -4,9 +4,19 @@
 package sqlmock
 
 import (
+	"context"
+	"database/sql/driver"
 	"io"
+	"reflect"
+	"time"
 )
 
+var _ driver.RowsColumnTypeDatabaseTypeName = (*rowSets)(nil)
+var _ driver.RowsColumnTypeLength = (*rowSets)(nil)
+var _ driver.RowsColumnTypeNullable = (*rowSets)(nil)
+var _ driver.RowsColumnTypePrecisionScale = (*rowSets)(nil)
+var _ driver.RowsColumnTypeScanType = (*rowSets)(nil)
+
 // Implement the "RowsNextResultSet" interface
 func (rs *rowSets) HasNextResultSet() bool {
 	return rs.pos+1 < len(rs.sets)
@@ -21,3 +31,54 @@ func (rs *rowSets) NextResultSet() error {
 	rs.pos++
 	return nil
 }
+
+// Implement the "RowsColumnTypeDatabaseTypeName" interface
+func (rs *rowSets) ColumnTypeDatabaseTypeName(index int) string {
+	return rs.sets[rs.pos].column(index).DbType()
+}
+
+// Implement the "RowsColumnTypeLength" interface
+func (rs *rowSets) ColumnTypeLength(index int) (int64, bool) {
+	return rs.sets[rs.pos].column(index).Length()
+}
+
+// Implement the "RowsColumnTypeNullable" interface
+func (rs *rowSets) ColumnTypeNullable(index int) (bool, bool) {
+	return rs.sets[rs.pos].column(index).IsNullable()
+}
+
+// Implement the "RowsColumnTypePrecisionScale" interface
+func (rs *rowSets) ColumnTypePrecisionScale(index int) (int64, int64, bool) {
+	return rs.sets[rs.pos].column(index).PrecisionScale()
+}
+
+// Implement the "RowsColumnTypeScanType" interface
+func (rs *rowSets) ColumnTypeScanType(index int) reflect.Type {
+	return rs.sets[rs.pos].column(index).ScanType()
+}
+
+// context returns the context of the query which returned the rows
+func (rs *rowSets) context() context.Context {
+	if rs.ctx == nil {
+		return context.Background()
+	}
+	return rs.ctx
+}
+
+// await waits for the delay before the next row is read, the rows
+// are cancelled as soon as the query context is done
+func (rs *rowSets) await(delay time.Duration) error {
+	ctx := rs.context()
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ErrCancelled
+		}
+	}
+	if ctx.Err() != nil {
+		return ErrCancelled
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -204,3 +205,83 @@ func TestQueryRowBytesNotInvalidatedByClose_jsonRawMessageIntoCustomBytes(t *tes
 	}
 	queryRowBytesNotInvalidatedByClose(t, rows, scan, []byte(`{"thing": "one", "thing2": "two"}`))
 }
+
+func TestQueryColumnTypes(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rs := mock.NewRowsWithColumnDefinition(
+		mock.NewColumn("id").OfType("BIGINT", int64(0)).Nullable(false),
+		mock.NewColumn("name").OfType("VARCHAR", "").Nullable(true).WithLength(255),
+		mock.NewColumn("price").OfType("DECIMAL", float64(0)).WithPrecisionAndScale(10, 2),
+	).AddRow(1, "apple", 1.99)
+	mock.ExpectSql(Query(), "SELECT (.+) FROM products").WillReturnRows(rs)
+
+	rows, err := db.Query("SELECT id, name, price FROM products")
+	if err != nil {
+		t.Fatalf("error '%s' was not expected while retrieving mock rows", err)
+	}
+	defer rows.Close()
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatalf("error '%s' was not expected while retrieving column types", err)
+	}
+	if len(types) != 3 {
+		t.Fatalf("expected 3 column types, but got %d", len(types))
+	}
+
+	if name := types[0].DatabaseTypeName(); name != "BIGINT" {
+		t.Errorf("expected id database type to be BIGINT, but got %s", name)
+	}
+	if st := types[0].ScanType(); st != reflect.TypeOf(int64(0)) {
+		t.Errorf("expected id scan type to be int64, but got %s", st)
+	}
+	if nullable, ok := types[0].Nullable(); !ok || nullable {
+		t.Errorf("expected id not to be nullable, but got %v, %v", nullable, ok)
+	}
+	if length, ok := types[1].Length(); !ok || length != 255 {
+		t.Errorf("expected name length to be 255, but got %d, %v", length, ok)
+	}
+	if nullable, ok := types[1].Nullable(); !ok || !nullable {
+		t.Errorf("expected name to be nullable, but got %v, %v", nullable, ok)
+	}
+	if precision, scale, ok := types[2].DecimalSize(); !ok || precision != 10 || scale != 2 {
+		t.Errorf("expected price decimal size to be 10, 2, but got %d, %d, %v", precision, scale, ok)
+	}
+	if _, ok := types[2].Length(); ok {
+		t.Error("expected price length not to be defined")
+	}
+}
+
+func TestQueryColumnTypesWithoutDefinition(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectSql(Query(), "SELECT (.+) FROM products").WillReturnRows(NewRows([]string{"id"}).AddRow(1))
+
+	rows, err := db.Query("SELECT id FROM products")
+	if err != nil {
+		t.Fatalf("error '%s' was not expected while retrieving mock rows", err)
+	}
+	defer rows.Close()
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatalf("error '%s' was not expected while retrieving column types", err)
+	}
+	if types[0].Name() != "id" || types[0].DatabaseTypeName() != "" {
+		t.Errorf("expected an undefined id column, but got %q of type %q", types[0].Name(), types[0].DatabaseTypeName())
+	}
+	if st := types[0].ScanType(); st.Kind() != reflect.Interface {
+		t.Errorf("expected scan type to be interface{}, but got %s", st)
+	}
+}
@@ -0,0 +1,114 @@
+package sqlmock
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"time"
+)
+
+// RowIterator fills dest with the values of the row at index i,
+// it returns io.EOF once there are no more rows. Any other error
+// is returned by the rows as if it occurred while reading row i.
+type RowIterator func(i int, dest []driver.Value) error
+
+// rowSource reads the row at index i like a RowIterator,
+// it stops waiting for the row once ctx is done
+type rowSource func(ctx context.Context, i int, dest []driver.Value) error
+
+// NewRowsFromIterator allows Rows to be generated lazily, row by
+// row, as they are read. It is meant for large result sets which
+// would be too memory heavy to add with AddRow. The iterator is
+// started over from row 0 every time the rows are returned.
+// Use Sqlmock.NewRowsFromIterator instead if using a custom converter
+func NewRowsFromIterator(columns []string, next RowIterator) *Rows {
+	return newRowsFromSource(columns, func(_ context.Context, i int, dest []driver.Value) error {
+		return next(i, dest)
+	})
+}
+
+func newRowsFromSource(columns []string, source rowSource) *Rows {
+	r := NewRows(columns)
+	r.iter = source
+	return r
+}
+
+// NewRowsFromChannel allows Rows to be read lazily from a channel,
+// every value received is a row and closing the channel ends the
+// rows. Unlike an iterator a channel cannot be read again, so the
+// rows should be returned by a single query. The rows are cancelled
+// with ErrCancelled if the query context is done while waiting for
+// a value.
+// Use Sqlmock.NewRowsFromChannel instead if using a custom converter
+func NewRowsFromChannel(columns []string, ch <-chan []driver.Value) *Rows {
+	return newRowsFromSource(columns, func(ctx context.Context, i int, dest []driver.Value) error {
+		var row []driver.Value
+		var ok bool
+		select {
+		case row, ok = <-ch:
+		case <-ctx.Done():
+			return ErrCancelled
+		}
+		if !ok {
+			return io.EOF
+		}
+		if len(row) != len(dest) {
+			return fmt.Errorf("row #%d: expected %d values to match the number of columns, but got %d", i+1, len(dest), len(row))
+		}
+		copy(dest, row)
+		return nil
+	})
+}
+
+// WithRowDelay sets a delay applied before every row is read,
+// simulating a slow database cursor. The rows are cancelled with
+// ErrCancelled if the query context is done while waiting.
+func (r *Rows) WithRowDelay(d time.Duration) *Rows {
+	r.rowDelay = d
+	return r
+}
+
+// RowDelay sets a delay applied before the given row is read,
+// overriding the delay set by WithRowDelay for that row.
+func (r *Rows) RowDelay(row int, d time.Duration) *Rows {
+	if r.delays == nil {
+		r.delays = make(map[int]time.Duration)
+	}
+	r.delays[row] = d
+	return r
+}
+
+// delay returns the delay applied before reading the given row
+func (r *Rows) delay(row int) time.Duration {
+	if d, ok := r.delays[row]; ok {
+		return d
+	}
+	return r.rowDelay
+}
+
+// next reads the row at the cursor and advances it
+func (r *Rows) next(ctx context.Context) ([]driver.Value, error) {
+	if r.iter == nil {
+		r.pos++
+		if r.pos > len(r.rows) {
+			return nil, io.EOF // per interface spec
+		}
+		return r.rows[r.pos-1], nil
+	}
+
+	row := make([]driver.Value, len(r.cols))
+	if err := r.iter(ctx, r.pos, row); err != nil {
+		return nil, err
+	}
+	r.pos++
+
+	for i, v := range row {
+		v, err := r.converter.ConvertValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("row #%d, column #%d (%q) type %T: %s", r.pos, i, r.cols[i], row[i], err)
+		}
+		row[i] = v
+	}
+	return row, nil
+}
@@ -0,0 +1,236 @@
+package sqlmock
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestQueryRowsFromIterator(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	const total = 100000
+	rows := mock.NewRowsFromIterator([]string{"id", "title"}, func(i int, dest []driver.Value) error {
+		if i == total {
+			return io.EOF
+		}
+		dest[0] = i
+		dest[1] = fmt.Sprintf("post %d", i)
+		return nil
+	})
+	mock.ExpectSql(nil, "SELECT").WillReturnRows(rows).Times(2)
+
+	for n := 0; n < 2; n++ {
+		rs, err := db.Query("SELECT id, title FROM posts")
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected while querying", err)
+		}
+
+		var count int
+		for rs.Next() {
+			var id int64
+			var title string
+			if err := rs.Scan(&id, &title); err != nil {
+				t.Fatalf("an error '%s' was not expected while scanning", err)
+			}
+			if id != int64(count) || title != fmt.Sprintf("post %d", count) {
+				t.Fatalf("unexpected row %d: %d, %s", count, id, title)
+			}
+			count++
+		}
+		if err := rs.Err(); err != nil {
+			t.Fatalf("an error '%s' was not expected while iterating", err)
+		}
+		rs.Close()
+
+		if count != total {
+			t.Errorf("expected %d rows, but got %d", total, count)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestQueryRowsFromIteratorError(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := NewRowsFromIterator([]string{"id"}, func(i int, dest []driver.Value) error {
+		if i == 3 {
+			return fmt.Errorf("connection lost")
+		}
+		dest[0] = i
+		return nil
+	})
+	mock.ExpectSql(nil, "SELECT").WillReturnRows(rows)
+
+	rs, err := db.Query("SELECT id FROM posts")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected while querying", err)
+	}
+	defer rs.Close()
+
+	var count int
+	for rs.Next() {
+		count++
+	}
+	if count != 3 {
+		t.Errorf("expected 3 rows before the error, but got %d", count)
+	}
+	if err := rs.Err(); err == nil || err.Error() != "connection lost" {
+		t.Errorf("expected the iterator error, but got: %v", err)
+	}
+}
+
+func TestQueryRowsFromChannelWithDelay(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ch := make(chan []driver.Value)
+	go func() {
+		defer close(ch)
+		for i := 0; i < 3; i++ {
+			ch <- []driver.Value{i}
+		}
+	}()
+
+	rows := NewRowsFromChannel([]string{"id"}, ch).WithRowDelay(10 * time.Millisecond)
+	mock.ExpectSql(nil, "SELECT").WillReturnRows(rows)
+
+	start := time.Now()
+	rs, err := db.Query("SELECT id FROM posts")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected while querying", err)
+	}
+	defer rs.Close()
+
+	var ids []int
+	for rs.Next() {
+		var id int
+		if err := rs.Scan(&id); err != nil {
+			t.Fatalf("an error '%s' was not expected while scanning", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rs.Err(); err != nil {
+		t.Fatalf("an error '%s' was not expected while iterating", err)
+	}
+
+	if fmt.Sprint(ids) != "[0 1 2]" {
+		t.Errorf("expected ids [0 1 2], but got %v", ids)
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("expected rows to be delayed, but reading took %s", elapsed)
+	}
+}
+
+func TestQueryRowsCancelledMidIteration(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := NewRows([]string{"id"}).
+		AddRow(1).
+		AddRow(2).
+		AddRow(3).
+		RowDelay(1, time.Second)
+	mock.ExpectSql(nil, "SELECT").WillReturnRows(rows)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rs, err := db.QueryContext(ctx, "SELECT id FROM posts")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected while querying", err)
+	}
+	defer rs.Close()
+
+	if !rs.Next() {
+		t.Fatalf("expected the first row to be read without delay, but got: %v", rs.Err())
+	}
+
+	time.AfterFunc(10*time.Millisecond, cancel)
+	start := time.Now()
+	if rs.Next() {
+		t.Fatal("expected the rows to be cancelled while waiting for the second row")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected the rows to stop promptly, but it took %s", elapsed)
+	}
+	if err := rs.Err(); err != ErrCancelled && err != context.Canceled {
+		t.Errorf("expected a cancellation error, but got: %v", err)
+	}
+}
+
+func TestQueryRowsFromChannelCancelled(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ch := make(chan []driver.Value, 1)
+	ch <- []driver.Value{1}
+	mock.ExpectSql(nil, "SELECT").WillReturnRows(NewRowsFromChannel([]string{"id"}, ch))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rs, err := db.QueryContext(ctx, "SELECT id FROM posts")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected while querying", err)
+	}
+	defer rs.Close()
+
+	if !rs.Next() {
+		t.Fatalf("expected the first row to be read, but got: %v", rs.Err())
+	}
+
+	// the channel is never closed, the cursor waits for the next value
+	time.AfterFunc(10*time.Millisecond, cancel)
+	done := make(chan bool)
+	go func() { done <- rs.Next() }()
+
+	select {
+	case next := <-done:
+		if next {
+			t.Fatal("expected the rows to be cancelled while waiting for the second row")
+		}
+	case <-time.After(time.Second):
+		close(ch) // unblock the cursor to close the rows
+		t.Fatal("expected the rows to stop once the query context is cancelled")
+	}
+	if err := rs.Err(); err != ErrCancelled && err != context.Canceled {
+		t.Errorf("expected a cancellation error, but got: %v", err)
+	}
+}
+
+func TestRowDelayOverridesRowsDelay(t *testing.T) {
+	rows := NewRows([]string{"id"}).
+		WithRowDelay(time.Second).
+		RowDelay(0, 0)
+
+	if d := rows.delay(0); d != 0 {
+		t.Errorf("expected no delay for row 0, but got %s", d)
+	}
+	if d := rows.delay(1); d != time.Second {
+		t.Errorf("expected a delay of 1s for row 1, but got %s", d)
+	}
+}
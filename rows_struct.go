@@ -0,0 +1,187 @@
+package sqlmock
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// StructRowsOption configures how NewRowsFromStructs
+// maps struct fields to columns.
+type StructRowsOption func(*structRowsConfig)
+
+type structRowsConfig struct {
+	tags       []string
+	nameMapper func(string) string
+	converter  driver.ValueConverter
+}
+
+// StructTags sets the struct tags looked up for column names, in order
+// of precedence. By default the "db", "sql" and "json" tags are used.
+func StructTags(tags ...string) StructRowsOption {
+	return func(c *structRowsConfig) {
+		c.tags = tags
+	}
+}
+
+// StructNameMapper sets the function naming the column of a field
+// without any of the looked up tags. By default the field name is
+// lower cased.
+func StructNameMapper(mapper func(field string) string) StructRowsOption {
+	return func(c *structRowsConfig) {
+		c.nameMapper = mapper
+	}
+}
+
+func withStructConverter(converter driver.ValueConverter) StructRowsOption {
+	return func(c *structRowsConfig) {
+		c.converter = converter
+	}
+}
+
+// structField is the path to a column value within a struct
+type structField struct {
+	column string
+	index  []int
+}
+
+// NewRowsFromStructs creates Rows from a slice of structs or struct
+// pointers, a single struct is accepted as well. Every exported field
+// is a column, named by its struct tag, fields tagged "-" are skipped
+// and the fields of embedded structs are promoted as columns of their
+// own. Values are converted with the Rows converter, so pointers,
+// sql.Null* types and any driver.Valuer are supported. A nil struct
+// pointer is a row of NULL values.
+// Use Sqlmock.NewRowsFromStructs instead if using a custom converter
+func NewRowsFromStructs(structs interface{}, opts ...StructRowsOption) *Rows {
+	cfg := &structRowsConfig{
+		tags:       []string{"db", "sql", "json"},
+		nameMapper: strings.ToLower,
+		converter:  driver.DefaultParameterConverter,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	v := reflect.ValueOf(structs)
+	if !v.IsValid() {
+		panic("NewRowsFromStructs expects a slice of structs, but got nil")
+	}
+
+	var items []reflect.Value
+	typ := v.Type()
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, v.Index(i))
+		}
+		typ = typ.Elem()
+		if typ.Kind() == reflect.Interface && len(items) > 0 {
+			typ = items[0].Elem().Type()
+		}
+	default:
+		items = append(items, v)
+	}
+
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("NewRowsFromStructs expects a slice of structs, but got %s", v.Type()))
+	}
+
+	fields := cfg.fields(typ, nil)
+	cols := make([]string, len(fields))
+	for i, f := range fields {
+		cols[i] = f.column
+	}
+
+	r := NewRows(cols)
+	r.converter = cfg.converter
+	for _, item := range items {
+		r.AddRow(structValues(item, fields)...)
+	}
+	return r
+}
+
+// fields lists the columns of a struct type, promoting the
+// fields of embedded structs which are not tagged
+func (c *structRowsConfig) fields(typ reflect.Type, index []int) []structField {
+	var fields []structField
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue // unexported
+		}
+
+		name, tagged := c.columnName(f)
+		if name == "-" {
+			continue
+		}
+
+		path := append(append([]int{}, index...), i)
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && !tagged && ft.Kind() == reflect.Struct && !isValuer(f.Type) {
+			fields = append(fields, c.fields(ft, path)...)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue // unexported embedded non struct
+		}
+		fields = append(fields, structField{column: name, index: path})
+	}
+	return fields
+}
+
+func (c *structRowsConfig) columnName(f reflect.StructField) (string, bool) {
+	for _, tag := range c.tags {
+		if value, ok := f.Tag.Lookup(tag); ok {
+			name := strings.Split(value, ",")[0]
+			if name != "" {
+				return name, true
+			}
+		}
+	}
+	return c.nameMapper(f.Name), false
+}
+
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+func isValuer(typ reflect.Type) bool {
+	return typ.Implements(valuerType) || reflect.PtrTo(typ).Implements(valuerType)
+}
+
+// structValues reads the column values of a struct, a nil struct
+// pointer is read as a row of NULL, as are the fields of a nil
+// embedded struct pointer
+func structValues(item reflect.Value, fields []structField) []driver.Value {
+	for item.Kind() == reflect.Ptr || item.Kind() == reflect.Interface {
+		item = item.Elem()
+	}
+
+	values := make([]driver.Value, len(fields))
+	if !item.IsValid() {
+		return values
+	}
+	for i, f := range fields {
+		v := item
+		for _, idx := range f.index {
+			if v.Kind() == reflect.Ptr {
+				if v.IsNil() {
+					v = reflect.Value{}
+					break
+				}
+				v = v.Elem()
+			}
+			v = v.Field(idx)
+		}
+		if v.IsValid() {
+			values[i] = v.Interface()
+		}
+	}
+	return values
+}
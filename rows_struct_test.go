@@ -0,0 +1,160 @@
+package sqlmock
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type structRowsBase struct {
+	ID      int64     `db:"id"`
+	Created time.Time `db:"created_at"`
+}
+
+type structRowsAudit struct {
+	Editor string `json:"editor,omitempty"`
+}
+
+type structRowsUser struct {
+	structRowsBase
+	*structRowsAudit
+	Name     string         `db:"name"`
+	Email    sql.NullString `sql:"email"`
+	Nick     *string
+	Password string `db:"-"`
+	internal int
+}
+
+func TestRowsFromStructs(t *testing.T) {
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	nick := "jd"
+	users := []*structRowsUser{
+		{
+			structRowsBase:  structRowsBase{ID: 1, Created: created},
+			structRowsAudit: &structRowsAudit{Editor: "admin"},
+			Name:            "john",
+			Email:           sql.NullString{String: "john@example.com", Valid: true},
+			Nick:            &nick,
+			Password:        "secret",
+		},
+		{
+			structRowsBase: structRowsBase{ID: 2, Created: created},
+			Name:           "jane",
+		},
+	}
+
+	rs := NewRowsFromStructs(users)
+
+	cols := []string{"id", "created_at", "editor", "name", "email", "nick"}
+	if !reflect.DeepEqual(rs.cols, cols) {
+		t.Errorf("expected columns %v, but got %v", cols, rs.cols)
+	}
+
+	want := [][]driver.Value{
+		{int64(1), created, "admin", "john", "john@example.com", "jd"},
+		{int64(2), created, nil, "jane", nil, nil},
+	}
+	if !reflect.DeepEqual(rs.rows, want) {
+		t.Errorf("expected rows %v, but got %v", want, rs.rows)
+	}
+}
+
+func TestRowsFromStructsOptions(t *testing.T) {
+	type item struct {
+		ID    int    `db:"id" col:"item_id"`
+		Title string `db:"title"`
+		Body  string
+	}
+
+	rs := NewRowsFromStructs(
+		item{ID: 1, Title: "hello", Body: "world"},
+		StructTags("col"),
+		StructNameMapper(strings.ToUpper),
+	)
+
+	cols := []string{"item_id", "TITLE", "BODY"}
+	if !reflect.DeepEqual(rs.cols, cols) {
+		t.Errorf("expected columns %v, but got %v", cols, rs.cols)
+	}
+
+	want := [][]driver.Value{{int64(1), "hello", "world"}}
+	if !reflect.DeepEqual(rs.rows, want) {
+		t.Errorf("expected rows %v, but got %v", want, rs.rows)
+	}
+}
+
+func TestRowsFromStructsEmptySlice(t *testing.T) {
+	rs := NewRowsFromStructs([]structRowsBase{})
+
+	cols := []string{"id", "created_at"}
+	if !reflect.DeepEqual(rs.cols, cols) {
+		t.Errorf("expected columns %v, but got %v", cols, rs.cols)
+	}
+	if len(rs.rows) != 0 {
+		t.Errorf("expected no rows, but got %v", rs.rows)
+	}
+}
+
+func TestRowsFromStructsNilPointer(t *testing.T) {
+	type user struct {
+		ID int64
+	}
+	rs := NewRowsFromStructs([]*user{{1}, nil})
+
+	want := [][]driver.Value{{int64(1)}, {nil}}
+	if !reflect.DeepEqual(rs.rows, want) {
+		t.Errorf("expected rows %v, but got %v", want, rs.rows)
+	}
+}
+
+func TestRowsFromStructsNotStruct(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a slice of non struct values")
+		}
+	}()
+	NewRowsFromStructs([]int{1, 2})
+}
+
+func TestQueryRowsFromStructs(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	type user struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+
+	rows := mock.NewRowsFromStructs([]user{{1, "john"}, {2, "jane"}})
+	mock.ExpectSql(nil, "SELECT id, name FROM users").WillReturnRows(rows)
+
+	rs, err := db.Query("SELECT id, name FROM users")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected while querying", err)
+	}
+	defer rs.Close()
+
+	var got []user
+	for rs.Next() {
+		var u user
+		if err := rs.Scan(&u.ID, &u.Name); err != nil {
+			t.Fatalf("an error '%s' was not expected while scanning", err)
+		}
+		got = append(got, u)
+	}
+
+	want := []user{{1, "john"}, {2, "jane"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected users %v, but got %v", want, got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -12,6 +12,7 @@ package sqlmock
 
 import (
 	"database/sql/driver"
+	"sync"
 )
 
 // Common interface serves to create expectations
@@ -25,14 +26,38 @@ type Common interface {
 
 	// ExpectationsWereMet checks whether all queued expectations
 	// were met in order. If any of them was not met - an error is returned.
+	// Expectations marked with Maybe are not required to be met.
+	// The returned *UnmetExpectationsError reports every unmet
+	// expectation and everything left open at once.
 	ExpectationsWereMet() error
 
+	// UnmetExpectations returns a message for every expectation
+	// which was not met and for every prepared statement or rows
+	// which were not closed, it is empty when all were met.
+	UnmetExpectations() []string
+
+	// Report describes the unmet expectations and the calls
+	// which did happen, to be used in custom test output.
+	Report() string
+
+	// UnexpectedCalls returns the journal of the calls which did
+	// not match the expectations, ExpectationsWereMet fails when
+	// it is not empty. Use StrictOption to fail at once instead.
+	UnexpectedCalls() []UnexpectedCall
+
 	// ExpectPrepare expects Prepare() to be called with expectedSQL query.
 	// the *ExpectedPrepare allows to mock database response.
 	// Note that you may expect Query() or Exec() on the *ExpectedPrepare
 	// statement to prevent repeating expectedSQL
 	ExpectPrepare(expectedSQL string) *ExpectedPrepare
 
+	// ExpectConnect expects the driver to open a new connection.
+	// the *ExpectedConnect allows to mock a failing connection
+	// attempt. Connect expectations are matched apart from the
+	// order of the other expectations, since database/sql opens
+	// connections whenever the pool needs them.
+	ExpectConnect() *ExpectedConnect
+
 	// ExpectBegin expects *sql.DB.Begin to be called.
 	// the *ExpectedBegin allows to mock database response
 	ExpectBegin() *ExpectedBegin
@@ -41,6 +66,12 @@ type Common interface {
 	// the *ExpectedCommit allows to mock database response
 	ExpectCommit() *ExpectedCommit
 
+	// ExpectTx expects a transaction to begin, to run the statements
+	// declared through the TxExpecter by fn and to be committed.
+	// Statements of the group fail to match when they are executed
+	// outside of the transaction or after it has finished.
+	ExpectTx(fn func(tx TxExpecter)) *ExpectedTx
+
 	// ExpectRollback expects *sql.Tx.Rollback to be called.
 	// the *ExpectedRollback allows to mock database response
 	ExpectRollback() *ExpectedRollback
@@ -69,12 +100,48 @@ type Common interface {
 	// expectations will be expected in order
 	MatchExpectationsInOrder(bool)
 
+	// ConnStats returns the number of connections the driver
+	// opened and closed so far, useful to assert pool usage
+	// and connection leaks.
+	ConnStats() ConnStats
+
 	// NewRows allows Rows to be created from a
 	// sql driver.Value slice or from the CSV string and
 	// to be used as sql driver.Rows.
 	NewRows(columns []string) *Rows
 
+	// NewRowsWithColumnDefinition allows Rows to be created with
+	// column definitions describing their metadata, using the
+	// mock converter.
+	NewRowsWithColumnDefinition(columns ...*Column) *Rows
+
+	// NewRowsFromStructs allows Rows to be created from a slice
+	// of structs, using the mock converter.
+	NewRowsFromStructs(structs interface{}, opts ...StructRowsOption) *Rows
+
+	// NewRowsFromIterator allows Rows to be generated lazily,
+	// using the mock converter.
+	NewRowsFromIterator(columns []string, next RowIterator) *Rows
+
+	// NewRowsFromChannel allows Rows to be read lazily from
+	// a channel, using the mock converter.
+	NewRowsFromChannel(columns []string, ch <-chan []driver.Value) *Rows
+
+	// NewColumn creates a Column definition with the given name.
+	NewColumn(name string) *Column
+
+	// ExpectSql expects a Query or an Exec to be triggered, the operation
+	// is matched with expectedOpt against "query" or "exec", use nil for
+	// either of them.
 	ExpectSql(expectedOpt Matcher, expectedSQL string) *ExpectedSql
+
+	// ExpectQuery expects Query() or QueryRow() to be called with expectedSQL query.
+	// the *ExpectedQuery allows to mock database response.
+	ExpectQuery(expectedSQL string) *ExpectedQuery
+
+	// ExpectExec expects Exec() to be called with expectedSQL query.
+	// the *ExpectedExec allows to mock database response
+	ExpectExec(expectedSQL string) *ExpectedExec
 }
 
 type sqlmock struct {
@@ -86,5 +153,25 @@ type sqlmock struct {
 	queryMatcher QueryMatcher
 	monitorPings bool
 
+	isolationLevels map[driver.IsolationLevel]bool
+
 	expected []expectation
+
+	// connects and closes count the connections
+	// opened and closed by the driver
+	connects int
+	closes   int
+
+	connectExpected []*ExpectedConnect
+
+	// connLock guards the state of connections
+	// and transactions opened by the driver
+	connLock sync.Mutex
+	txCount  int
+
+	// journal records the unexpected calls, strict
+	// reports each of them as soon as it happens
+	journalLock sync.Mutex
+	journal     []UnexpectedCall
+	strict      func(call UnexpectedCall)
 }
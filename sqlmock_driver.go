@@ -11,64 +11,69 @@ The driver allows to mock any sql driver method behavior.
 package sqlmock
 
 import (
+	"database/sql"
 	"database/sql/driver"
 	"fmt"
 	"time"
 )
 
-var _ driver.Conn = (*sqlmock)(nil)
-var _ driver.Tx = (*sqlmock)(nil)
+var _ driver.Conn = (*conn)(nil)
+var _ driver.Tx = (*conn)(nil)
 
 // Close a mock database driver connection. It may or may not
 // be called depending on the circumstances, but if it is called
 // there must be an *ExpectedClose expectation satisfied.
 // meets http://golang.org/pkg/database/sql/driver/#Conn interface
-func (c *sqlmock) Close() error {
+func (c *conn) Close() (err error) {
+	defer func() {
+		// the pool closes connections on its own, so
+		// they are only unexpected when Close is expected
+		if c.closeExpected() {
+			err = c.record("Close", err)
+		}
+	}()
 	c.drv.Lock()
 	defer c.drv.Unlock()
 
 	c.opened--
+	c.closes++
+	// the pool redials through the connector it got
+	// from OpenConnector, so the dsn is not needed anymore
 	if c.opened == 0 {
 		delete(c.drv.connMap, c.dsn)
 	}
 
-	var expected *ExpectedClose
-	var fulfilled int
-	var ok bool
-	for _, next := range c.expected {
-		next.Lock()
-		if next.fulfilled() {
-			next.Unlock()
-			fulfilled++
-			continue
+	const call = "call to database Close"
+	next, err := c.nextExpectation(call, func(e expectation) (bool, error) {
+		expected, ok := e.(*ExpectedClose)
+		if !ok {
+			return false, nil
 		}
-
-		if expected, ok = next.(*ExpectedClose); ok {
-			break
-		}
-
-		next.Unlock()
-		if c.ordered {
-			return fmt.Errorf("call to database Close, was not expected, next expectation is: %s", next)
-		}
-	}
-
-	if expected == nil {
-		msg := "call to database Close was not expected"
-		if fulfilled == len(c.expected) {
-			msg = "all expectations were already fulfilled, " + msg
-		}
-		return fmt.Errorf(msg)
+		return true, c.verify(&expected.commonExpectation, call)
+	})
+	if err != nil {
+		return err
 	}
 
-	expected.triggered = true
+	expected := next.(*ExpectedClose)
+	expected.triggered++
 	expected.Unlock()
 	return expected.err
 }
 
+// closeExpected tells whether any Close is expected
+func (c *conn) closeExpected() bool {
+	for _, e := range c.expected {
+		if _, ok := e.(*ExpectedClose); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // Begin meets http://golang.org/pkg/database/sql/driver/#Conn interface
-func (c *sqlmock) Begin() (driver.Tx, error) {
-	ex, err := c.begin()
+func (c *conn) Begin() (driver.Tx, error) {
+	ex, err := c.begin(driver.TxOptions{})
 	if ex != nil {
 		time.Sleep(ex.delay)
 	}
@@ -76,46 +81,43 @@ func (c *sqlmock) Begin() (driver.Tx, error) {
 		return nil, err
 	}
 
-	return c, nil
+	return c.beginTx(ex), nil
 }
 
-func (c *sqlmock) begin() (*ExpectedBegin, error) {
-	var expected *ExpectedBegin
-	var ok bool
-	var fulfilled int
-	for _, next := range c.expected {
-		next.Lock()
-		if next.fulfilled() {
-			next.Unlock()
-			fulfilled++
-			continue
-		}
+func (c *conn) begin(opts driver.TxOptions) (_ *ExpectedBegin, err error) {
+	defer func() { err = c.record("Begin", err) }()
+	if c.isolationLevels != nil && opts.Isolation != driver.IsolationLevel(sql.LevelDefault) && !c.isolationLevels[opts.Isolation] {
+		return nil, newError(ErrUnsupportedIsolation, "sqlmock: unsupported isolation level: %s", sql.IsolationLevel(opts.Isolation))
+	}
 
-		if expected, ok = next.(*ExpectedBegin); ok {
-			break
+	const call = "call to database transaction Begin"
+	next, err := c.nextExpectation(call, func(e expectation) (bool, error) {
+		expected, ok := e.(*ExpectedBegin)
+		if !ok {
+			return false, nil
 		}
-
-		next.Unlock()
-		if c.ordered {
-			return nil, fmt.Errorf("call to database transaction Begin, was not expected, next expectation is: %s", next)
+		if err := c.verify(&expected.commonExpectation, call); err != nil {
+			return true, err
 		}
-	}
-	if expected == nil {
-		msg := "call to database transaction Begin was not expected"
-		if fulfilled == len(c.expected) {
-			msg = "all expectations were already fulfilled, " + msg
+		if err := expected.checkTxOptions(opts); err != nil {
+			return true, wrapError(ErrUnexpectedCall, err, "%s: %s", call, err)
 		}
-		return nil, fmt.Errorf(msg)
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	expected.triggered = true
+	expected := next.(*ExpectedBegin)
+
+	expected.triggered++
 	expected.Unlock()
 
-	return expected, expected.err
+	return expected, c.markBad(expected.err)
 }
 
 // Prepare meets http://golang.org/pkg/database/sql/driver/#Conn interface
-func (c *sqlmock) Prepare(query string) (driver.Stmt, error) {
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
 	ex, err := c.prepare(query)
 	if ex != nil {
 		time.Sleep(ex.delay)
@@ -124,122 +126,68 @@ func (c *sqlmock) Prepare(query string) (driver.Stmt, error) {
 		return nil, err
 	}
 
-	return &statement{c, ex, query}, nil
+	return ex.newStatement(c, query), nil
 }
 
-func (c *sqlmock) prepare(query string) (*ExpectedPrepare, error) {
-	var expected *ExpectedPrepare
-	var fulfilled int
-	var ok bool
-
-	for _, next := range c.expected {
-		next.Lock()
-		if next.fulfilled() {
-			next.Unlock()
-			fulfilled++
-			continue
-		}
-
-		if c.ordered {
-			if expected, ok = next.(*ExpectedPrepare); ok {
-				break
-			}
-
-			next.Unlock()
-			return nil, fmt.Errorf("call to Prepare statement with query '%s', was not expected, next expectation is: %s", query, next)
-		}
-
-		if pr, ok := next.(*ExpectedPrepare); ok {
-			if err := c.queryMatcher.Match(pr.expectSQL, query); err == nil {
-				expected = pr
-				break
-			}
+func (c *conn) prepare(query string) (_ *ExpectedPrepare, err error) {
+	defer func() { err = c.record("Prepare", err) }()
+	call := fmt.Sprintf("call to Prepare '%s' query", query)
+	next, err := c.nextExpectation(call, func(e expectation) (bool, error) {
+		expected, ok := e.(*ExpectedPrepare)
+		if !ok || c.queryMatcher.Match(expected.expectSQL, query) != nil {
+			return false, nil
 		}
-		next.Unlock()
+		return true, c.verify(&expected.commonExpectation, call)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if expected == nil {
-		msg := "call to Prepare '%s' query was not expected"
-		if fulfilled == len(c.expected) {
-			msg = "all expectations were already fulfilled, " + msg
-		}
-		return nil, fmt.Errorf(msg, query)
-	}
+	expected := next.(*ExpectedPrepare)
 	defer expected.Unlock()
-	if err := c.queryMatcher.Match(expected.expectSQL, query); err != nil {
-		return nil, fmt.Errorf("prepare: %v", err)
-	}
-
-	expected.triggered = true
-	return expected, expected.err
+	expected.triggered++
+	expected.txs = append(expected.txs, c.currentTx())
+	return expected, c.markBad(expected.err)
 }
 
 // Commit meets http://golang.org/pkg/database/sql/driver/#Tx
-func (c *sqlmock) Commit() error {
-	var expected *ExpectedCommit
-	var fulfilled int
-	var ok bool
-	for _, next := range c.expected {
-		next.Lock()
-		if next.fulfilled() {
-			next.Unlock()
-			fulfilled++
-			continue
-		}
-
-		if expected, ok = next.(*ExpectedCommit); ok {
-			break
-		}
-
-		next.Unlock()
-		if c.ordered {
-			return fmt.Errorf("call to Commit transaction, was not expected, next expectation is: %s", next)
-		}
-	}
-	if expected == nil {
-		msg := "call to Commit transaction was not expected"
-		if fulfilled == len(c.expected) {
-			msg = "all expectations were already fulfilled, " + msg
-		}
-		return fmt.Errorf(msg)
+func (c *conn) Commit() (err error) {
+	defer func() { err = c.record("Commit", err) }()
+	const call = "call to Commit transaction"
+	next, err := c.nextExpectation(call, func(e expectation) (bool, error) {
+		expected, ok := e.(*ExpectedCommit)
+		if !ok {
+			return false, nil
+		}
+		return true, c.verify(&expected.commonExpectation, call)
+	})
+	if err != nil {
+		return err
 	}
 
-	expected.triggered = true
+	expected := next.(*ExpectedCommit)
+	expected.triggered++
 	expected.Unlock()
-	return expected.err
+	return c.markBad(expected.err)
 }
 
 // Rollback meets http://golang.org/pkg/database/sql/driver/#Tx
-func (c *sqlmock) Rollback() error {
-	var expected *ExpectedRollback
-	var fulfilled int
-	var ok bool
-	for _, next := range c.expected {
-		next.Lock()
-		if next.fulfilled() {
-			next.Unlock()
-			fulfilled++
-			continue
-		}
-
-		if expected, ok = next.(*ExpectedRollback); ok {
-			break
-		}
-
-		next.Unlock()
-		if c.ordered {
-			return fmt.Errorf("call to Rollback transaction, was not expected, next expectation is: %s", next)
-		}
-	}
-	if expected == nil {
-		msg := "call to Rollback transaction was not expected"
-		if fulfilled == len(c.expected) {
-			msg = "all expectations were already fulfilled, " + msg
-		}
-		return fmt.Errorf(msg)
+func (c *conn) Rollback() (err error) {
+	defer func() { err = c.record("Rollback", err) }()
+	const call = "call to Rollback transaction"
+	next, err := c.nextExpectation(call, func(e expectation) (bool, error) {
+		expected, ok := e.(*ExpectedRollback)
+		if !ok {
+			return false, nil
+		}
+		return true, c.verify(&expected.commonExpectation, call)
+	})
+	if err != nil {
+		return err
 	}
 
-	expected.triggered = true
+	expected := next.(*ExpectedRollback)
+	expected.triggered++
 	expected.Unlock()
-	return expected.err
+	return c.markBad(expected.err)
 }
@@ -11,10 +11,10 @@ import (
 	"time"
 )
 
-var _ driver.QueryerContext = (*sqlmock)(nil)
-var _ driver.ConnPrepareContext = (*sqlmock)(nil)
-var _ driver.ExecerContext = (*sqlmock)(nil)
-var _ driver.ConnBeginTx = (*sqlmock)(nil)
+var _ driver.QueryerContext = (*conn)(nil)
+var _ driver.ConnPrepareContext = (*conn)(nil)
+var _ driver.ExecerContext = (*conn)(nil)
+var _ driver.ConnBeginTx = (*conn)(nil)
 
 // Sqlmock interface for Go 1.8+
 type Sqlmock interface {
@@ -27,10 +27,16 @@ type Sqlmock interface {
 var ErrCancelled = errors.New("canceling query due to user request")
 
 // QueryContext Implement the "QueryerContext" interface
-func (c *sqlmock) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
-	ex, err := c.doSql("query", query, args)
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.query(ctx, nil, query, args)
+}
+
+// query runs a query on the connection directly
+// or through the given prepared statement
+func (c *conn) query(ctx context.Context, stmt *statement, query string, args []driver.NamedValue) (driver.Rows, error) {
+	ex, err := c.doSql(ctx, stmt, "query", query, args)
 	if ex == nil {
-		return nil, err
+		return nil, c.record("Query", err)
 	}
 
 	select {
@@ -38,17 +44,24 @@ func (c *sqlmock) QueryContext(ctx context.Context, query string, args []driver.
 		if err != nil {
 			return nil, err
 		}
-		return ex.rows, nil
+		rows, err := ex.queryRows(ctx, query, args)
+		return rows, c.record("Query", err)
 	case <-ctx.Done():
 		return nil, ErrCancelled
 	}
 }
 
 // ExecContext Implement the "ExecerContext" interface
-func (c *sqlmock) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
-	ex, err := c.doSql("exec", query, args)
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.exec(ctx, nil, query, args)
+}
+
+// exec runs an exec on the connection directly
+// or through the given prepared statement
+func (c *conn) exec(ctx context.Context, stmt *statement, query string, args []driver.NamedValue) (driver.Result, error) {
+	ex, err := c.doSql(ctx, stmt, "exec", query, args)
 	if ex == nil {
-		return nil, err
+		return nil, c.record("Exec", err)
 	}
 
 	select {
@@ -56,15 +69,16 @@ func (c *sqlmock) ExecContext(ctx context.Context, query string, args []driver.N
 		if err != nil {
 			return nil, err
 		}
-		return ex.result, nil
+		res, err := ex.execResult(ctx, query, args)
+		return res, c.record("Exec", err)
 	case <-ctx.Done():
 		return nil, ErrCancelled
 	}
 }
 
 // BeginTx Implement the "ConnBeginTx" interface
-func (c *sqlmock) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
-	ex, err := c.begin()
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	ex, err := c.begin(opts)
 	if ex == nil {
 		return nil, err
 	}
@@ -74,14 +88,14 @@ func (c *sqlmock) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx
 		if err != nil {
 			return nil, err
 		}
-		return c, nil
+		return c.beginTx(ex), nil
 	case <-ctx.Done():
 		return nil, ErrCancelled
 	}
 }
 
 // PrepareContext Implement the "ConnPrepareContext" interface
-func (c *sqlmock) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
 	ex, err := c.prepare(query)
 	if ex == nil {
 		return nil, err
@@ -92,14 +106,14 @@ func (c *sqlmock) PrepareContext(ctx context.Context, query string) (driver.Stmt
 		if err != nil {
 			return nil, err
 		}
-		return &statement{c, ex, query}, nil
+		return ex.newStatement(c, query), nil
 	case <-ctx.Done():
 		return nil, ErrCancelled
 	}
 }
 
 // Ping Implement the "Pinger" interface - the explicit DB driver ping was only added to database/sql in Go 1.8
-func (c *sqlmock) Ping(ctx context.Context) error {
+func (c *conn) Ping(ctx context.Context) error {
 	if !c.monitorPings {
 		return nil
 	}
@@ -117,154 +131,135 @@ func (c *sqlmock) Ping(ctx context.Context) error {
 	}
 }
 
-func (c *sqlmock) ping() (*ExpectedPing, error) {
-	var expected *ExpectedPing
-	var fulfilled int
-	var ok bool
-	for _, next := range c.expected {
-		next.Lock()
-		if next.fulfilled() {
-			next.Unlock()
-			fulfilled++
-			continue
-		}
-
-		if expected, ok = next.(*ExpectedPing); ok {
-			break
+func (c *conn) ping() (_ *ExpectedPing, err error) {
+	defer func() { err = c.record("Ping", err) }()
+	const call = "call to database Ping"
+	next, err := c.nextExpectation(call, func(e expectation) (bool, error) {
+		expected, ok := e.(*ExpectedPing)
+		if !ok {
+			return false, nil
 		}
-
-		next.Unlock()
-		if c.ordered {
-			return nil, fmt.Errorf("call to database Ping, was not expected, next expectation is: %s", next)
-		}
-	}
-
-	if expected == nil {
-		msg := "call to database Ping was not expected"
-		if fulfilled == len(c.expected) {
-			msg = "all expectations were already fulfilled, " + msg
-		}
-		return nil, fmt.Errorf(msg)
+		return true, c.verify(&expected.commonExpectation, call)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	expected.triggered = true
+	expected := next.(*ExpectedPing)
+	expected.triggered++
 	expected.Unlock()
-	return expected, expected.err
+	return expected, c.markBad(expected.err)
 }
 
 // Query meets http://golang.org/pkg/database/sql/driver/#Queryer
 // Deprecated: Drivers should implement QueryerContext instead.
-func (c *sqlmock) Query(query string, args []driver.Value) (driver.Rows, error) {
-	ex, err := c.doSql("query", query, convNameValue(args))
-	if ex != nil {
-		time.Sleep(ex.delay)
-	}
-	if err != nil {
-		return nil, err
-	}
-
-	return ex.rows, nil
+func (c *conn) Query(query string, args []driver.Value) (driver.Rows, error) {
+	return c.query(context.Background(), nil, query, convNameValue(args))
 }
 
-func (c *sqlmock) doSql(opt string, query string, args []driver.NamedValue) (*ExpectedSql, error) {
-	var expected *ExpectedSql
-	var fulfilled int
-	var ok bool
-	for _, next := range c.expected {
-		next.Lock()
-		if next.fulfilled() {
-			next.Unlock()
-			fulfilled++
-			continue
-		}
+func (c *conn) doSql(ctx context.Context, stmt *statement, opt string, query string, args []driver.NamedValue) (*ExpectedSql, error) {
+	call := "Query"
+	if opt == "exec" {
+		call = "ExecQuery"
+	}
 
-		if c.ordered {
-			if expected, ok = next.(*ExpectedSql); ok {
-				break
-			}
-			next.Unlock()
-			return nil, fmt.Errorf("call to Query '%s' with args %+v, was not expected, next expectation is: %s", query, args, next)
+	var rejected []rejection
+	var last *ExpectedSql
+	var pending, required bool
+	desc := fmt.Sprintf("call to %s '%s' with args %+v", call, query, args)
+	next, err := c.nextExpectation(desc, func(e expectation) (bool, error) {
+		qr, ok := e.(*ExpectedSql)
+		last, pending, required = qr, true, c.ordered && !e.fulfilled()
+		if !ok {
+			return false, nil
 		}
-
-		if qr, ok := next.(*ExpectedSql); ok {
-			if qr.expectedOpt != nil && !qr.expectedOpt.Match(opt) {
-				return nil, fmt.Errorf("operation not match, expected:%s", opt)
-			}
-
-			if err := c.queryMatcher.Match(qr.expectSQL, query); err != nil {
-				next.Unlock()
-				continue
-			}
-
-			if qr.checkArgs != nil {
-				if err := qr.checkArgs(convValue(args)); err == nil {
-					expected = qr
-					break
-				}
-			} else {
-				if err := qr.attemptArgMatch(args); err == nil {
-					expected = qr
-					break
-				}
+		// an already fulfilled expectation which may still be
+		// repeated is only taken in order when it fully matches
+		err := c.sqlMatches(qr, stmt, opt, query, args)
+		if err != nil {
+			rejected = append(rejected, rejection{qr, err})
+		}
+		return true, err
+	})
+	if err != nil {
+		switch {
+		case required && last != nil:
+			// the next expectation in order did not match
+			msg := "%s, was not expected, next expectation does not match"
+			if !last.matchesOp(opt) {
+				msg = "%s, was not expected, next expectation is for another operation"
 			}
+			candidates := c.candidates(rejected[len(rejected)-1:], stmt, opt, query, args)
+			return nil, newMismatchError(fmt.Sprintf(msg, desc), call, query, args, candidates)
+		case required:
+			return nil, err
 		}
-		next.Unlock()
-	}
 
-	if expected == nil {
-		msg := "call to Query '%s' with args %+v was not expected"
-		if fulfilled == len(c.expected) {
+		msg := desc + " was not expected"
+		if !pending {
 			msg = "all expectations were already fulfilled, " + msg
 		}
-		return nil, fmt.Errorf(msg, query, args)
+		return nil, newMismatchError(msg, call, query, args, c.candidates(rejected, stmt, opt, query, args))
 	}
 
+	expected := next.(*ExpectedSql)
 	defer expected.Unlock()
 
-	if expected.expectedOpt != nil && !expected.expectedOpt.Match(opt) {
-		return nil, fmt.Errorf("operation not match, expected:%s", opt)
+	tx := c.currentTx()
+	expected.triggered++
+	expected.txs = append(expected.txs, tx)
+	expected.calls = append(expected.calls, newCall(ctx, query, args, tx))
+	if expected.checkArgs == nil {
+		expected.captureArgs(args)
+	}
+	if expected.err != nil {
+		return expected, c.markBad(expected.err) // mocked to return error
 	}
 
-	if err := c.queryMatcher.Match(expected.expectSQL, query); err != nil {
-		return nil, fmt.Errorf("query: %v", err)
+	if opt == "query" && expected.rows == nil && expected.respond == nil {
+		return nil, newError(ErrNoResult, "query '%s' with args %+v, must return a database/sql/driver.Rows, but it was not set for expectation %T as %+v", query, args, expected, expected)
 	}
 
-	if expected.checkArgs != nil {
-		if err := expected.checkArgs(convValue(args)); err != nil {
-			return nil, fmt.Errorf("query '%s', arguments do not match: %s", query, err)
-		}
-	} else {
-		if err := expected.attemptArgMatch(args); err != nil {
-			return nil, fmt.Errorf("query '%s', arguments do not match: %s", query, err)
-		}
+	if opt == "exec" && expected.result == nil && expected.respond == nil {
+		return nil, newError(ErrNoResult, "ExecQuery '%s' with args %+v, must return a database/sql/driver.Result, but it was not set for expectation %T as %+v", query, args, expected, expected)
 	}
 
-	expected.triggered = true
-	if expected.err != nil {
-		return expected, expected.err // mocked to return error
+	return expected, nil
+}
+
+// sqlMatches checks whether the operation, query, connection,
+// transaction and arguments of a call satisfy the given sql expectation
+func (c *conn) sqlMatches(e *ExpectedSql, stmt *statement, opt string, query string, args []driver.NamedValue) error {
+	if !e.matchesOp(opt) {
+		return newError(ErrUnexpectedCall, "operation %s is not expected", opt)
+	}
+
+	if err := c.queryMatcher.Match(e.expectSQL, query); err != nil {
+		return err
 	}
 
-	if opt == "query" && expected.rows == nil {
-		return nil, fmt.Errorf("query '%s' with args %+v, must return a database/sql/driver.Rows, but it was not set for expectation %T as %+v", query, args, expected, expected)
+	if err := e.checkStmt(stmt, "the call"); err != nil {
+		return err
 	}
 
-	if opt == "exec" && expected.result == nil {
-		return nil, fmt.Errorf("ExecQuery '%s' with args %+v, must return a database/sql/driver.Result, but it was not set for expectation %T as %+v", query, args, expected, expected)
+	if err := c.verify(&e.commonExpectation, "the call"); err != nil {
+		return err
 	}
 
-	return expected, nil
+	if e.checkArgs != nil {
+		if err := e.checkArgs(convValue(args)); err != nil {
+			return &ArgsMismatchError{Err: err}
+		}
+		return nil
+	}
+	if err := e.attemptArgMatch(args); err != nil {
+		return &ArgsMismatchError{Err: err}
+	}
+	return nil
 }
 
 // Exec meets http://golang.org/pkg/database/sql/driver/#Execer
 // Deprecated: Drivers should implement ExecerContext instead.
-func (c *sqlmock) Exec(query string, args []driver.Value) (driver.Result, error) {
-	ex, err := c.doSql("exec", query, convNameValue(args))
-	if ex != nil {
-		time.Sleep(ex.delay)
-	}
-	if err != nil {
-		return nil, err
-	}
-
-	return ex.result, nil
+func (c *conn) Exec(query string, args []driver.Value) (driver.Result, error) {
+	return c.exec(context.Background(), nil, query, convNameValue(args))
 }
@@ -6,7 +6,9 @@ package sqlmock
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 )
@@ -639,3 +641,175 @@ func TestPingExpectationsContextTimeout(t *testing.T) {
 		t.Errorf("expected Ping to return after context timeout, but it did not in a timely fashion")
 	}
 }
+
+func TestContextWillRespond(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectSql(Exec(), "INSERT INTO users").
+		WithArgs(Any()).
+		WillRespond(func(ctx context.Context, query string, args []driver.NamedValue) (*Rows, driver.Result, error) {
+			if args[0].Value == "bad" {
+				return nil, nil, errors.New("invalid name")
+			}
+			return nil, NewResult(int64(len(args[0].Value.(string))), 1), nil
+		}).
+		Times(2)
+	mock.ExpectSql(Query(), "SELECT name FROM users WHERE id = ?").
+		WithArgs(Any()).
+		WillRespond(func(ctx context.Context, query string, args []driver.NamedValue) (*Rows, driver.Result, error) {
+			return NewRows([]string{"name"}).AddRow(fmt.Sprintf("user-%d", args[0].Value)), nil, nil
+		})
+
+	res, err := db.ExecContext(context.Background(), "INSERT INTO users(name) VALUES (?)", "john")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if id, _ := res.LastInsertId(); id != 4 {
+		t.Errorf("expected last insert id to be 4, but got %d", id)
+	}
+
+	if _, err = db.ExecContext(context.Background(), "INSERT INTO users(name) VALUES (?)", "bad"); err == nil || err.Error() != "invalid name" {
+		t.Errorf("expected the responder error, but got: %v", err)
+	}
+
+	var name string
+	if err := db.QueryRowContext(context.Background(), "SELECT name FROM users WHERE id = ?", 7).Scan(&name); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if name != "user-7" {
+		t.Errorf("expected name to be user-7, but got %s", name)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestContextWillRespondCancel(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	var called bool
+	mock.ExpectSql(Query(), "SELECT name FROM users").
+		WillDelayFor(time.Second).
+		WillRespond(func(ctx context.Context, query string, args []driver.NamedValue) (*Rows, driver.Result, error) {
+			called = true
+			return NewRows([]string{"name"}), nil, nil
+		})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := db.QueryContext(ctx, "SELECT name FROM users"); err != ErrCancelled {
+		t.Errorf("was expecting cancel error, but got: %v", err)
+	}
+	if called {
+		t.Error("responder was not expected to be called for a cancelled query")
+	}
+}
+
+func TestContextBeginTxOptions(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin().WithIsolation(sql.LevelSerializable).WithReadOnly(true)
+	mock.ExpectRollback()
+	errReadOnly := errors.New("read-only transactions are not allowed here")
+	mock.ExpectBegin().WithTxOptionsCheck(func(opts driver.TxOptions) error {
+		if opts.ReadOnly {
+			return errReadOnly
+		}
+		return nil
+	})
+
+	tx, err := db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true})
+	if err != nil {
+		t.Fatalf("error was not expected, but got: %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Errorf("error was not expected, but got: %v", err)
+	}
+
+	_, err = db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	if !errors.Is(err, errReadOnly) || !errors.Is(err, ErrUnexpectedCall) {
+		t.Errorf("expected the options check error, but got: %v", err)
+	}
+}
+
+func TestContextBeginTxOptionsSelectBegin(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	// a begin with other options is skipped once fulfilled in order
+	mock.ExpectBegin().WithIsolation(sql.LevelSerializable).AnyTimes()
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("error was not expected, but got: %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Errorf("error was not expected, but got: %v", err)
+	}
+
+	// a begin with other options is skipped when unordered
+	mock.MatchExpectationsInOrder(false)
+	mock.ExpectBegin().WithReadOnly(true)
+	mock.ExpectBegin().WithReadOnly(false)
+	mock.ExpectRollback()
+	mock.ExpectRollback()
+	for _, readOnly := range []bool{false, true} {
+		tx, err := db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: readOnly})
+		if err != nil {
+			t.Fatalf("read-only %t: error was not expected, but got: %v", readOnly, err)
+		}
+		if err := tx.Rollback(); err != nil {
+			t.Errorf("error was not expected, but got: %v", err)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestContextBeginUnsupportedIsolation(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New(SupportedIsolationLevelsOption(sql.LevelReadCommitted, sql.LevelSerializable))
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+
+	_, err = db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelSnapshot})
+	if err == nil || err.Error() != "sqlmock: unsupported isolation level: Snapshot" {
+		t.Errorf("expected an unsupported isolation level error, but got: %v", err)
+	}
+
+	if _, err := db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelSerializable}); err != nil {
+		t.Errorf("error was not expected, but got: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -9,7 +9,7 @@ import (
 )
 
 // CheckNamedValue meets https://golang.org/pkg/database/sql/driver/#NamedValueChecker
-func (c *sqlmock) CheckNamedValue(nv *driver.NamedValue) (err error) {
+func (c *conn) CheckNamedValue(nv *driver.NamedValue) (err error) {
 	switch nv.Value.(type) {
 	case sql.Out:
 		return nil
@@ -64,7 +64,7 @@ func Test_sqlmock_CheckNamedValue(t *testing.T) {
 	for i := range tests {
 		tt := tests[i]
 		t.Run(tt.name, func(t *testing.T) {
-			if err := mock.(*sqlmock).CheckNamedValue(tt.arg); (err != nil) != tt.wantErr {
+			if err := (&conn{sqlmock: mock.(*sqlmock)}).CheckNamedValue(tt.arg); (err != nil) != tt.wantErr {
 				t.Errorf("CheckNamedValue() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
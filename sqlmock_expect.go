@@ -15,6 +15,7 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"log"
+	"strings"
 )
 
 func (c *sqlmock) ExpectPing() *ExpectedPing {
@@ -40,25 +41,30 @@ func (c *sqlmock) ExpectSql(expectedOpt Matcher, expectedSQL string) *ExpectedSq
 	return e
 }
 
+func (c *sqlmock) ExpectQuery(expectedSQL string) *ExpectedQuery {
+	e := c.ExpectSql(Query(), expectedSQL)
+	e.op = "query"
+	return &ExpectedQuery{ex: e}
+}
+
+func (c *sqlmock) ExpectExec(expectedSQL string) *ExpectedExec {
+	e := c.ExpectSql(Exec(), expectedSQL)
+	e.op = "exec"
+	return &ExpectedExec{ex: e}
+}
+
 func (c *sqlmock) open(options []func(*sqlmock) error) (*sql.DB, Sqlmock, error) {
 	db, err := sql.Open("sqlmock", c.dsn)
 	if err != nil {
 		return db, c, err
 	}
 
-	for _, option := range options {
-		err := option(c)
-		if err != nil {
-			return db, c, err
-		}
-	}
-
-	if c.converter == nil {
-		c.converter = driver.DefaultParameterConverter
-	}
-
-	if c.queryMatcher == nil {
-		c.queryMatcher = QueryMatcherRegexp
+	if err := c.configure(options); err != nil {
+		// no connection was opened, which would unregister the dsn once closed
+		c.drv.Lock()
+		delete(c.drv.connMap, c.dsn)
+		c.drv.Unlock()
+		return db, c, err
 	}
 
 	if c.monitorPings {
@@ -72,6 +78,24 @@ func (c *sqlmock) open(options []func(*sqlmock) error) (*sql.DB, Sqlmock, error)
 	return db, c, db.Ping()
 }
 
+// configure applies the options and defaults
+func (c *sqlmock) configure(options []func(*sqlmock) error) error {
+	for _, option := range options {
+		if err := option(c); err != nil {
+			return err
+		}
+	}
+
+	if c.converter == nil {
+		c.converter = driver.DefaultParameterConverter
+	}
+
+	if c.queryMatcher == nil {
+		c.queryMatcher = QueryMatcherRegexp
+	}
+	return nil
+}
+
 func (c *sqlmock) ExpectClose() *ExpectedClose {
 	e := &ExpectedClose{}
 	c.expected = append(c.expected, e)
@@ -83,30 +107,91 @@ func (c *sqlmock) MatchExpectationsInOrder(b bool) {
 }
 
 func (c *sqlmock) ExpectationsWereMet() error {
-	for _, e := range c.expected {
+	report := c.report()
+	if len(report.Unexpected) == 0 && len(report.Unmet) == 0 && len(report.NotClosed) == 0 {
+		return nil
+	}
+	return report
+}
+
+func (c *sqlmock) UnmetExpectations() []string {
+	return c.report().Problems()
+}
+
+func (c *sqlmock) Report() string {
+	return c.report().Report()
+}
+
+// report checks every expectation, it collects the unmet
+// ones, everything left open and the calls which happened
+func (c *sqlmock) report() *UnmetExpectationsError {
+	report := &UnmetExpectationsError{Unexpected: c.UnexpectedCalls()}
+	check := func(e expectation) bool {
 		e.Lock()
 		fulfilled := e.fulfilled()
+		calls := e.describeCalls()
+		triggered := e.triggeredTimes()
 		e.Unlock()
 
+		if triggered > 0 {
+			report.Called = append(report.Called, fmt.Sprintf("%s (called %s)", summary(e), pluralTimes(triggered)))
+		}
 		if !fulfilled {
-			return fmt.Errorf("there is a remaining expectation which was not matched: %s", e)
+			report.Unmet = append(report.Unmet, UnmetExpectation{Expectation: e, Calls: calls})
 		}
+		return fulfilled
+	}
 
-		// for expected prepared statement check whether it was closed if expected
-		if prep, ok := e.(*ExpectedPrepare); ok {
-			if prep.mustBeClosed && !prep.wasClosed {
-				return fmt.Errorf("expected prepared statement to be closed, but it was not: %s", prep)
+	for _, e := range c.connectExpected {
+		check(e)
+	}
+
+	for _, e := range c.expected {
+		if !check(e) {
+			continue
+		}
+
+		// for expected prepared statement check whether each one prepared was closed if expected
+		if prep, ok := e.(*ExpectedPrepare); ok && prep.mustBeClosed {
+			prep.Lock()
+			for i, stmt := range prep.statements {
+				if !stmt.closed {
+					report.NotClosed = append(report.NotClosed, &StmtNotClosedError{Expectation: prep, Call: i + 1, calls: len(prep.statements)})
+				}
 			}
+			prep.Unlock()
 		}
 
 		// must check whether all expected queried rows are closed
-		if query, ok := e.(*ExpectedSql); ok {
-			if query.rowsMustBeClosed && !query.rowsWereClosed {
-				return fmt.Errorf("expected query rows to be closed, but it was not: %s", query)
+		if query, ok := e.(*ExpectedSql); ok && query.rowsMustBeClosed {
+			query.Lock()
+			for i, rows := range query.returnedRows {
+				if !rows.closed {
+					report.NotClosed = append(report.NotClosed, &RowsNotClosedError{Expectation: query, Call: i + 1, calls: len(query.returnedRows)})
+				}
 			}
+			query.Unlock()
 		}
 	}
-	return nil
+	return report
+}
+
+// summary is the first line describing an expectation,
+// followed by the sql it matches when there is one
+func summary(e expectation) string {
+	lines := strings.Split(e.String(), "\n")
+	for _, line := range lines[1:] {
+		if strings.HasPrefix(line, "  - matches sql: ") {
+			return lines[0] + " " + strings.TrimPrefix(line, "  - ")
+		}
+	}
+	return lines[0]
+}
+
+func (c *sqlmock) ExpectConnect() *ExpectedConnect {
+	e := &ExpectedConnect{}
+	c.connectExpected = append(c.connectExpected, e)
+	return e
 }
 
 func (c *sqlmock) ExpectBegin() *ExpectedBegin {
@@ -141,3 +226,39 @@ func (c *sqlmock) NewRows(columns []string) *Rows {
 	r.converter = c.converter
 	return r
 }
+
+// NewRowsWithColumnDefinition allows Rows to be created with
+// column definitions describing their metadata, using the
+// mock converter.
+func (c *sqlmock) NewRowsWithColumnDefinition(columns ...*Column) *Rows {
+	r := NewRowsWithColumnDefinition(columns...)
+	r.converter = c.converter
+	return r
+}
+
+// NewRowsFromStructs allows Rows to be created from a slice
+// of structs, using the mock converter.
+func (c *sqlmock) NewRowsFromStructs(structs interface{}, opts ...StructRowsOption) *Rows {
+	return NewRowsFromStructs(structs, append([]StructRowsOption{withStructConverter(c.converter)}, opts...)...)
+}
+
+// NewRowsFromIterator allows Rows to be generated lazily,
+// using the mock converter.
+func (c *sqlmock) NewRowsFromIterator(columns []string, next RowIterator) *Rows {
+	r := NewRowsFromIterator(columns, next)
+	r.converter = c.converter
+	return r
+}
+
+// NewRowsFromChannel allows Rows to be read lazily from
+// a channel, using the mock converter.
+func (c *sqlmock) NewRowsFromChannel(columns []string, ch <-chan []driver.Value) *Rows {
+	r := NewRowsFromChannel(columns, ch)
+	r.converter = c.converter
+	return r
+}
+
+// NewColumn creates a Column definition with the given name.
+func (c *sqlmock) NewColumn(name string) *Column {
+	return NewColumn(name)
+}
@@ -754,8 +754,8 @@ func TestRunExecsWithExpectedErrorMeetsExpectations(t *testing.T) {
 
 func TestRunQueryWithExpectedErrorMeetsExpectations(t *testing.T) {
 	db, dbmock, _ := New()
-	mock.ExpectSql(nil, "THE FIRST QUERY").WillReturnError(fmt.Errorf("big bad bug"))
-	mock.ExpectSql(nil, "THE SECOND QUERY").WillReturnRows(NewRows([]string{"col"}).AddRow(1))
+	dbmock.ExpectSql(nil, "THE FIRST QUERY").WillReturnError(fmt.Errorf("big bad bug"))
+	dbmock.ExpectSql(nil, "THE SECOND QUERY").WillReturnRows(NewRows([]string{"col"}).AddRow(1))
 
 	_, _ = db.Query("THE FIRST QUERY")
 	_, _ = db.Query("THE SECOND QUERY")
@@ -1238,7 +1238,7 @@ func Test_sqlmock_Prepare_and_Exec(t *testing.T) {
 	expectedRows := mock.NewRows([]string{"id", "name", "email"}).AddRow(1, "test", "test@example.com")
 	mock.ExpectSql(nil, "SELECT (.+) FROM users WHERE (.+)").WithArgs("test").WillReturnRows(expectedRows)
 
-	got, err := mock.(*sqlmock).Prepare(query)
+	got, err := (&conn{sqlmock: mock.(*sqlmock)}).Prepare(query)
 	if err != nil {
 		t.Error(err)
 		return
@@ -1278,7 +1278,7 @@ func Test_sqlmock_Exec(t *testing.T) {
 	defer db.Close()
 
 	mock.ExpectBegin()
-	_, err = mock.(*sqlmock).Exec("", []driver.Value{})
+	_, err = (&conn{sqlmock: mock.(*sqlmock)}).Exec("", []driver.Value{})
 	if err == nil {
 		t.Errorf("error expected")
 		return
@@ -1298,7 +1298,7 @@ func Test_sqlmock_Exec(t *testing.T) {
 
 	mock.(*sqlmock).expected = mock.(*sqlmock).expected[1:]
 	query := "SELECT name, email FROM users WHERE name = ?"
-	result, err := mock.(*sqlmock).Exec(query, []driver.Value{"test"})
+	result, err := (&conn{sqlmock: mock.(*sqlmock)}).Exec(query, []driver.Value{"test"})
 	if err != nil {
 		t.Error(err)
 		return
@@ -1309,13 +1309,13 @@ func Test_sqlmock_Exec(t *testing.T) {
 	}
 
 	failQuery := "SELECT name, sex FROM animals WHERE sex = ?"
-	_, err = mock.(*sqlmock).Exec(failQuery, []driver.Value{failArgument{}})
+	_, err = (&conn{sqlmock: mock.(*sqlmock)}).Exec(failQuery, []driver.Value{failArgument{}})
 	if err == nil {
 		t.Errorf("error expected")
 		return
 	}
 	mock.(*sqlmock).ordered = false
-	_, err = mock.(*sqlmock).Exec("", []driver.Value{failArgument{}})
+	_, err = (&conn{sqlmock: mock.(*sqlmock)}).Exec("", []driver.Value{failArgument{}})
 	if err == nil {
 		t.Errorf("error expected")
 		return
@@ -1331,13 +1331,13 @@ func Test_sqlmock_Query(t *testing.T) {
 	expectedRows := mock.NewRows([]string{"id", "name", "email"}).AddRow(1, "test", "test@example.com")
 	mock.ExpectSql(nil, "SELECT (.+) FROM users WHERE (.+)").WithArgs("test").WillReturnRows(expectedRows)
 	query := "SELECT name, email FROM users WHERE name = ?"
-	rows, err := mock.(*sqlmock).Query(query, []driver.Value{"test"})
+	rows, err := (&conn{sqlmock: mock.(*sqlmock)}).Query(query, []driver.Value{"test"})
 	if err != nil {
 		t.Error(err)
 		return
 	}
 	defer rows.Close()
-	_, err = mock.(*sqlmock).Query(query, []driver.Value{failArgument{}})
+	_, err = (&conn{sqlmock: mock.(*sqlmock)}).Query(query, []driver.Value{failArgument{}})
 	if err == nil {
 		t.Errorf("error expected")
 		return
@@ -1,20 +1,61 @@
 package sqlmock
 
-import "database/sql/driver"
+import (
+	"database/sql/driver"
+)
 
 var _ driver.Stmt = (*statement)(nil)
 
 type statement struct {
-	conn  *sqlmock
+	conn  *conn
 	ex    *ExpectedPrepare
 	query string
+
+	// closed is guarded by the lock of the expectation
+	closed bool
+}
+
+// newStatement returns a statement prepared for the expectation, which
+// keeps every statement it prepared to report each of them left open
+func (e *ExpectedPrepare) newStatement(c *conn, query string) *statement {
+	stmt := &statement{conn: c, ex: e, query: query}
+	e.Lock()
+	e.statements = append(e.statements, stmt)
+	e.Unlock()
+	return stmt
 }
 
 func (stmt *statement) Close() error {
-	stmt.ex.wasClosed = true
+	stmt.ex.Lock()
+	stmt.closed = true
+	stmt.ex.Unlock()
 	return stmt.ex.closeErr
 }
 
+// NumInput returns the number of arguments set by WithNumInput, or
+// counted from the query by WithNumInputFromSQL. Otherwise it returns
+// -1, database/sql does not check the number of arguments then.
 func (stmt *statement) NumInput() int {
+	if stmt.ex.numInputSet {
+		return stmt.ex.numInput
+	}
+	if stmt.ex.countInput {
+		return countPlaceholders(stmt.query)
+	}
 	return -1
 }
+
+// isClosed reports whether the statement was closed. database/sql
+// never uses a driver statement after closing it, so this only
+// guards statements used directly through the driver interfaces.
+func (stmt *statement) isClosed() bool {
+	stmt.ex.Lock()
+	defer stmt.ex.Unlock()
+	return stmt.closed
+}
+
+// closedErr reports a call made on the statement after it was closed
+func (stmt *statement) closedErr(call string) error {
+	err := newError(ErrStmtClosed, "call to %s on prepared statement '%s' was not expected, the statement is already closed", call, stmt.query)
+	return stmt.conn.record(call, err)
+}
@@ -14,22 +14,28 @@ var _ driver.StmtQueryContext = (*statement)(nil)
 
 // ExecContext Implement the "StmtExecContext" interface
 func (stmt *statement) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
-	return stmt.conn.ExecContext(ctx, stmt.query, args)
+	if stmt.isClosed() {
+		return nil, stmt.closedErr("Exec")
+	}
+	return stmt.conn.exec(ctx, stmt, stmt.query, args)
 }
 
 // QueryContext Implement the "StmtQueryContext" interface
 func (stmt *statement) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
-	return stmt.conn.QueryContext(ctx, stmt.query, args)
+	if stmt.isClosed() {
+		return nil, stmt.closedErr("Query")
+	}
+	return stmt.conn.query(ctx, stmt, stmt.query, args)
 }
 
 // Deprecated: Drivers should implement ExecerContext instead.
 func (stmt *statement) Exec(args []driver.Value) (driver.Result, error) {
-	return stmt.conn.ExecContext(context.Background(), stmt.query, convertValueToNamedValue(args))
+	return stmt.ExecContext(context.Background(), convertValueToNamedValue(args))
 }
 
 // Deprecated: Drivers should implement StmtQueryContext instead (or additionally).
 func (stmt *statement) Query(args []driver.Value) (driver.Rows, error) {
-	return stmt.conn.QueryContext(context.Background(), stmt.query, convertValueToNamedValue(args))
+	return stmt.QueryContext(context.Background(), convertValueToNamedValue(args))
 }
 
 func convertValueToNamedValue(args []driver.Value) []driver.NamedValue {
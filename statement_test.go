@@ -5,6 +5,7 @@ package sqlmock
 
 import (
 	"errors"
+	"strings"
 	"testing"
 )
 
@@ -32,3 +33,121 @@ func TestExpectedPreparedStatementCloseError(t *testing.T) {
 		t.Fatalf("got = %v, want = %v", err, want)
 	}
 }
+
+func TestPreparedStatementExpectations(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatal("failed to open sqlmock database:", err)
+	}
+	defer db.Close()
+
+	prep := mock.ExpectPrepare("SELECT name FROM users")
+	prep.ExpectQuery().WithArgs(1).WillReturnRows(NewRows([]string{"name"}).AddRow("john"))
+	prep.ExpectExec().WithArgs(2).WillReturnResult(NewResult(0, 0))
+
+	stmt, err := db.Prepare("SELECT name FROM users WHERE id = ?")
+	if err != nil {
+		t.Fatal("unexpected error while preparing a statement:", err)
+	}
+	defer stmt.Close()
+
+	var name string
+	if err := stmt.QueryRow(1).Scan(&name); err != nil {
+		t.Fatal("unexpected error while querying the statement:", err)
+	}
+	if name != "john" {
+		t.Errorf("expected name john, but got %s", name)
+	}
+	if _, err := stmt.Exec(2); err != nil {
+		t.Fatal("unexpected error while executing the statement:", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPreparedStatementExpectationNotMatchedByQuery(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatal("failed to open sqlmock database:", err)
+	}
+	defer db.Close()
+
+	prep := mock.ExpectPrepare("SELECT name FROM users")
+	prep.ExpectQuery().WillReturnRows(NewRows([]string{"name"}).AddRow("john"))
+
+	if _, err := db.Prepare("SELECT name FROM users"); err != nil {
+		t.Fatal("unexpected error while preparing a statement:", err)
+	}
+
+	_, err = db.Query("SELECT name FROM users")
+	if err == nil {
+		t.Fatal("expected an error for a query not made through the prepared statement")
+	}
+	if !strings.Contains(err.Error(), "but it was not called on a prepared statement") {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestPreparedStatementUsedAfterClose(t *testing.T) {
+	_, mock, err := New()
+	if err != nil {
+		t.Fatal("failed to open sqlmock database:", err)
+	}
+
+	prep := mock.ExpectPrepare("SELECT name FROM users")
+	prep.ExpectQuery().WillReturnRows(NewRows([]string{"name"}))
+
+	// database/sql never uses a driver statement once closed,
+	// so the statement is prepared on the driver connection
+	stmt, err := (&conn{sqlmock: mock.(*sqlmock)}).Prepare("SELECT name FROM users")
+	if err != nil {
+		t.Fatal("unexpected error while preparing a statement:", err)
+	}
+	if err := stmt.Close(); err != nil {
+		t.Fatal("unexpected error while closing the statement:", err)
+	}
+
+	_, err = stmt.Query(nil)
+	if err == nil || !strings.Contains(err.Error(), "the statement is already closed") {
+		t.Errorf("expected an error for a statement used after close, but got: %v", err)
+	}
+}
+
+func TestPreparedStatementNumInput(t *testing.T) {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatal("failed to open sqlmock database:", err)
+	}
+	defer db.Close()
+
+	mock.ExpectPrepare("INSERT INTO users").WithNumInputFromSQL().
+		ExpectExec().WithArgs(1, "john", "john@example.com").WillReturnResult(NewResult(1, 1))
+	mock.ExpectPrepare("DELETE FROM users").WithNumInput(1)
+
+	stmt, err := db.Prepare("INSERT INTO users (id, name, email) VALUES (?, ?, ?)")
+	if err != nil {
+		t.Fatal("unexpected error while preparing a statement:", err)
+	}
+	_, err = stmt.Exec(1, "john")
+	if err == nil || err.Error() != "sql: expected 3 arguments, got 2" {
+		t.Errorf("expected an argument count error, but got: %v", err)
+	}
+	if _, err := stmt.Exec(1, "john", "john@example.com"); err != nil {
+		t.Fatal("unexpected error while executing the statement:", err)
+	}
+
+	stmt, err = db.Prepare("DELETE FROM users WHERE id = $1")
+	if err != nil {
+		t.Fatal("unexpected error while preparing a statement:", err)
+	}
+	_, err = stmt.Exec()
+	if err == nil || err.Error() != "sql: expected 1 arguments, got 0" {
+		t.Errorf("expected an argument count error, but got: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
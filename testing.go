@@ -0,0 +1,48 @@
+package sqlmock
+
+import (
+	"database/sql"
+)
+
+// TestingT is the part of testing.TB used
+// to report the failures of the mock.
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+// TestingTB is the part of testing.TB used by NewT.
+type TestingTB interface {
+	TestingT
+	Fatalf(format string, args ...interface{})
+	Cleanup(f func())
+	Helper()
+}
+
+// NewT creates sqlmock database connection and a mock to manage
+// expectations for the test tb, like New does. The test fails at
+// once when the database cannot be opened.
+//
+// When the test finishes, the database is closed and the test fails
+// with tb.Errorf unless ExpectationsWereMet. Pass StrictOption(tb)
+// to fail the test at the moment an unexpected call occurs.
+func NewT(tb TestingTB, options ...func(*sqlmock) error) (*sql.DB, Sqlmock) {
+	tb.Helper()
+	db, mock, err := New(options...)
+	if err != nil {
+		if db != nil {
+			_ = db.Close()
+		}
+		tb.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		return nil, nil
+	}
+
+	tb.Cleanup(func() {
+		tb.Helper()
+		// connections closed here are only checked when Close is expected
+		_ = db.Close()
+		if err := mock.ExpectationsWereMet(); err != nil {
+			tb.Errorf("sqlmock: %s", err)
+		}
+	})
+	return db, mock
+}
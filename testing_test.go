@@ -0,0 +1,112 @@
+package sqlmock
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type fakeTB struct {
+	recordingT
+	fatals   []string
+	cleanups []func()
+	helpers  int
+}
+
+func (tb *fakeTB) Fatalf(format string, args ...interface{}) {
+	tb.fatals = append(tb.fatals, fmt.Sprintf(format, args...))
+}
+
+func (tb *fakeTB) Cleanup(f func()) {
+	tb.cleanups = append(tb.cleanups, f)
+}
+
+func (tb *fakeTB) Helper() {
+	tb.helpers++
+}
+
+func (tb *fakeTB) finish() {
+	for i := len(tb.cleanups) - 1; i >= 0; i-- {
+		tb.cleanups[i]()
+	}
+}
+
+func TestNewT(t *testing.T) {
+	db, mock := NewT(t)
+
+	mock.ExpectExec("UPDATE users").WillReturnResult(NewResult(0, 1))
+	if _, err := db.Exec("UPDATE users"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestNewTReportsUnmetExpectations(t *testing.T) {
+	tb := &fakeTB{}
+	db, mock := NewT(tb)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	if _, err := db.Begin(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(tb.errors) != 0 || len(tb.cleanups) != 1 {
+		t.Fatalf("expected nothing to be reported before cleanup, but got: %q", tb.errors)
+	}
+	tb.finish()
+
+	if len(tb.errors) != 1 || !strings.HasPrefix(tb.errors[0], "sqlmock: there is a remaining expectation which was not matched: ExpectedCommit") {
+		t.Errorf("expected the unmet commit to be reported, but got: %q", tb.errors)
+	}
+	if tb.helpers < 2 {
+		t.Errorf("expected the failure to be reported from helper frames, but got %d Helper calls", tb.helpers)
+	}
+	if err := db.Ping(); err == nil || err.Error() != "sql: database is closed" {
+		t.Errorf("expected the database to be closed, but got: %v", err)
+	}
+}
+
+func TestNewTStrict(t *testing.T) {
+	tb := &fakeTB{}
+	db, mock := NewT(tb, StrictOption(tb))
+
+	mock.ExpectBegin()
+	_, _ = db.Exec("DELETE FROM users")
+
+	if len(tb.errors) != 1 || !strings.HasPrefix(tb.errors[0], "sqlmock: unexpected call to Exec: ") {
+		t.Fatalf("expected the unexpected call to be reported at once, but got: %q", tb.errors)
+	}
+	helpers := tb.helpers
+
+	tb.finish()
+	if len(tb.errors) != 2 || !strings.HasPrefix(tb.errors[1], "sqlmock: there are 2 problems with the expectations:") {
+		t.Errorf("expected the cleanup to report the journal and the unmet begin, but got: %q", tb.errors)
+	}
+	if helpers < 2 {
+		t.Errorf("expected the strict report to call Helper, but got %d Helper calls", helpers)
+	}
+}
+
+func TestNewTFailsToOpen(t *testing.T) {
+	tb := &fakeTB{}
+	failing := func(*sqlmock) error { return fmt.Errorf("bad option") }
+
+	pool.Lock()
+	dsn := fmt.Sprintf("sqlmock_db_%d", pool.counter)
+	pool.Unlock()
+
+	db, mock := NewT(tb, failing)
+	if db != nil || mock != nil {
+		t.Error("expected no database nor mock when the database cannot be opened")
+	}
+	if len(tb.fatals) != 1 || !strings.Contains(tb.fatals[0], "bad option") || len(tb.cleanups) != 0 {
+		t.Errorf("expected the test to fail at once, but got: %q", tb.fatals)
+	}
+
+	pool.Lock()
+	_, ok := pool.connMap[dsn]
+	pool.Unlock()
+	if ok {
+		t.Errorf("expected the dsn %s to be unregistered", dsn)
+	}
+}
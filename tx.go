@@ -0,0 +1,145 @@
+package sqlmock
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+var _ driver.Tx = (*mockTx)(nil)
+
+// mockTx is the driver.Tx returned by Begin, it remembers
+// the expectation which started it, so statements expected
+// within a transaction can be verified to run inside of it
+type mockTx struct {
+	c     *conn
+	id    int
+	begin *ExpectedBegin
+	done  bool
+}
+
+func (tx *mockTx) String() string {
+	return fmt.Sprintf("#%d", tx.id)
+}
+
+// Commit meets http://golang.org/pkg/database/sql/driver/#Tx
+func (tx *mockTx) Commit() error {
+	defer tx.c.endTx(tx)
+	return tx.c.Commit()
+}
+
+// Rollback meets http://golang.org/pkg/database/sql/driver/#Tx
+func (tx *mockTx) Rollback() error {
+	defer tx.c.endTx(tx)
+	return tx.c.Rollback()
+}
+
+// beginTx starts a new transaction for the matched begin expectation
+func (c *conn) beginTx(ex *ExpectedBegin) *mockTx {
+	c.connLock.Lock()
+	defer c.connLock.Unlock()
+
+	c.txCount++
+	c.tx = &mockTx{c: c, id: c.txCount, begin: ex}
+	return c.tx
+}
+
+// endTx marks the transaction as done
+func (c *conn) endTx(tx *mockTx) {
+	c.connLock.Lock()
+	defer c.connLock.Unlock()
+
+	tx.done = true
+	if c.tx == tx {
+		c.tx = nil
+	}
+}
+
+// currentTx returns the transaction in progress, if any
+func (c *conn) currentTx() *mockTx {
+	c.connLock.Lock()
+	defer c.connLock.Unlock()
+	return c.tx
+}
+
+// verifyTx checks that an expectation declared within an ExpectTx
+// group is triggered inside the transaction started by that group
+func (c *conn) verifyTx(group *ExpectedTx, call string) error {
+	if group == nil {
+		return nil
+	}
+
+	tx := c.currentTx()
+	if tx == nil {
+		return newError(ErrWrongTx, "%s was expected to run inside a transaction, but it ran outside of any transaction, expected: %s", call, group)
+	}
+	if tx.begin != group.begin {
+		return newError(ErrWrongTx, "%s was expected to run inside a transaction, but it ran in another transaction %s, expected: %s", call, tx, group)
+	}
+	return nil
+}
+
+// TxExpecter is used to declare the expectations
+// of a transaction group created by ExpectTx.
+type TxExpecter interface {
+	// ExpectSql expects a query or exec to run inside the transaction.
+	ExpectSql(expectedOpt Matcher, expectedSQL string) *ExpectedSql
+
+	// ExpectQuery expects a query to run inside the transaction.
+	ExpectQuery(expectedSQL string) *ExpectedQuery
+
+	// ExpectExec expects an exec to run inside the transaction.
+	ExpectExec(expectedSQL string) *ExpectedExec
+
+	// ExpectPrepare expects a statement to be prepared inside the transaction.
+	ExpectPrepare(expectedSQL string) *ExpectedPrepare
+
+	// NewRows allows Rows to be created using the mock converter.
+	NewRows(columns []string) *Rows
+}
+
+type txExpecter struct {
+	c  *sqlmock
+	tx *ExpectedTx
+}
+
+func (t *txExpecter) ExpectSql(expectedOpt Matcher, expectedSQL string) *ExpectedSql {
+	e := t.c.ExpectSql(expectedOpt, expectedSQL)
+	e.group = t.tx
+	return e
+}
+
+func (t *txExpecter) ExpectQuery(expectedSQL string) *ExpectedQuery {
+	e := t.c.ExpectQuery(expectedSQL)
+	e.ex.group = t.tx
+	return e
+}
+
+func (t *txExpecter) ExpectExec(expectedSQL string) *ExpectedExec {
+	e := t.c.ExpectExec(expectedSQL)
+	e.ex.group = t.tx
+	return e
+}
+
+func (t *txExpecter) ExpectPrepare(expectedSQL string) *ExpectedPrepare {
+	e := t.c.ExpectPrepare(expectedSQL)
+	e.group = t.tx
+	return e
+}
+
+func (t *txExpecter) NewRows(columns []string) *Rows {
+	return t.c.NewRows(columns)
+}
+
+func (c *sqlmock) ExpectTx(fn func(tx TxExpecter)) *ExpectedTx {
+	tx := &ExpectedTx{mock: c}
+	tx.begin = c.ExpectBegin()
+
+	if fn != nil {
+		fn(&txExpecter{c: c, tx: tx})
+	}
+
+	commit := c.ExpectCommit()
+	commit.group = tx
+	tx.end = commit
+	return tx
+}
@@ -0,0 +1,191 @@
+package sqlmock
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+func TestExpectTx(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectTx(func(tx TxExpecter) {
+		tx.ExpectSql(Query(), "SELECT balance FROM accounts").
+			WillReturnRows(tx.NewRows([]string{"balance"}).AddRow(10))
+		tx.ExpectSql(Exec(), "UPDATE accounts").
+			WillReturnResult(NewResult(0, 1))
+	}).WithIsolation(sql.LevelSerializable)
+
+	tx, err := db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		t.Fatalf("unexpected error on begin: %s", err)
+	}
+
+	var balance int
+	if err := tx.QueryRow("SELECT balance FROM accounts").Scan(&balance); err != nil {
+		t.Fatalf("unexpected error on query: %s", err)
+	}
+	if _, err := tx.Exec("UPDATE accounts"); err != nil {
+		t.Fatalf("unexpected error on update: %s", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("unexpected error on commit: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestExpectTxGroupsUnordered(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.MatchExpectationsInOrder(false)
+	for i := 1; i <= 2; i++ {
+		id := int64(i)
+		mock.ExpectTx(func(tx TxExpecter) {
+			tx.ExpectExec("INSERT INTO users").WillReturnResult(NewResult(id, 1))
+		})
+	}
+
+	tx1, err := db.Begin()
+	if err != nil {
+		t.Fatalf("unexpected error on begin: %s", err)
+	}
+	tx2, err := db.Begin()
+	if err != nil {
+		t.Fatalf("unexpected error on begin: %s", err)
+	}
+
+	// the second transaction runs first, the statement and
+	// the commit of the first group are skipped
+	res, err := tx2.Exec("INSERT INTO users")
+	if err != nil {
+		t.Fatalf("unexpected error on insert: %s", err)
+	}
+	if id, _ := res.LastInsertId(); id != 2 {
+		t.Errorf("expected the insert of the second group to match, but got id %d", id)
+	}
+	if err := tx2.Commit(); err != nil {
+		t.Fatalf("unexpected error on commit: %s", err)
+	}
+
+	res, err = tx1.Exec("INSERT INTO users")
+	if err != nil {
+		t.Fatalf("unexpected error on insert: %s", err)
+	}
+	if id, _ := res.LastInsertId(); id != 1 {
+		t.Errorf("expected the insert of the first group to match, but got id %d", id)
+	}
+	if err := tx1.Commit(); err != nil {
+		t.Fatalf("unexpected error on commit: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestExpectTxStatementOutsideOfTx(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.MatchExpectationsInOrder(false)
+	mock.ExpectTx(func(tx TxExpecter) {
+		tx.ExpectSql(Exec(), "UPDATE accounts").WillReturnResult(NewResult(0, 1))
+	})
+
+	_, err = db.Exec("UPDATE accounts")
+	if err == nil || !strings.Contains(err.Error(), "outside of any transaction") {
+		t.Errorf("expected an error about the statement running outside of the transaction, but got: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err == nil {
+		t.Error("expected an error since the transaction was not run")
+	}
+}
+
+func TestExpectTxStatementAfterCommit(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.MatchExpectationsInOrder(false)
+	mock.ExpectTx(func(tx TxExpecter) {
+		tx.ExpectSql(Exec(), "UPDATE accounts").WillReturnResult(NewResult(0, 1))
+	})
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("unexpected error on begin: %s", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("unexpected error on commit: %s", err)
+	}
+
+	if _, err = db.Exec("UPDATE accounts"); err == nil {
+		t.Error("expected an error since the transaction was already committed")
+	}
+}
+
+func TestExpectTxWillRollback(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectTx(func(tx TxExpecter) {
+		tx.ExpectSql(Exec(), "UPDATE accounts").WillReturnError(sql.ErrNoRows)
+	}).WillRollback()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("unexpected error on begin: %s", err)
+	}
+	if _, err := tx.Exec("UPDATE accounts"); err != sql.ErrNoRows {
+		t.Errorf("expected sql.ErrNoRows, but got: %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("unexpected error on rollback: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestExpectTxOptionsMismatch(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectTx(nil).WithIsolation(sql.LevelSerializable).WithReadOnly(true)
+
+	_, err = db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelReadCommitted, ReadOnly: true})
+	if err == nil || !strings.Contains(err.Error(), "isolation level Serializable") {
+		t.Errorf("expected an isolation level mismatch error, but got: %v", err)
+	}
+}